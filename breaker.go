@@ -0,0 +1,291 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request
+// when the circuit breaker of the host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultConsecutiveFailures = 5
+	defaultMinRequests         = 10
+	defaultFailureWindow       = time.Minute
+	defaultOpenTimeout         = 30 * time.Second
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and counts failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a few trial requests through to decide
+	// whether the circuit closes or opens again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// BreakerPolicy configures the circuit breakers of a Session.
+//
+// The circuit of a host opens when either threshold is reached. If neither
+// ConsecutiveFailures nor FailureRate is set, the circuit opens after 5
+// consecutive failures.
+type BreakerPolicy struct {
+	// ConsecutiveFailures opens the circuit after this many failures in a row.
+	ConsecutiveFailures int
+
+	// FailureRate opens the circuit when the ratio of failures during
+	// Window reaches it, from 0 to 1.
+	FailureRate float64
+
+	// MinRequests is the number of requests needed during Window
+	// before FailureRate applies. Defaults to 10.
+	MinRequests int
+
+	// Window is the period over which FailureRate is computed.
+	// Defaults to 1 minute.
+	Window time.Duration
+
+	// OpenTimeout is how long the circuit stays open before letting
+	// trial requests through. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the number of trial requests allowed while half
+	// open, all of which must succeed to close the circuit. Defaults to 1.
+	HalfOpenRequests int
+
+	// IsFailure reports whether the result of a request is a failure.
+	// By default, errors and responses with a 5xx status code are failures.
+	// Requests cancelled by the caller are never recorded.
+	IsFailure func(resp *Response, err error) bool
+}
+
+// WithCircuitBreaker enables a circuit breaker per host on the session.
+func WithCircuitBreaker(p BreakerPolicy) SessionOption {
+	return func(s *Session) {
+		s.breaker = &breaker{policy: p, circuits: make(map[string]*circuit)}
+	}
+}
+
+// CircuitState returns the state of the circuit breaker of host.
+// It is CircuitClosed if the session has no circuit breaker.
+func (s *Session) CircuitState(host string) CircuitState {
+	if s.breaker == nil {
+		return CircuitClosed
+	}
+	return s.breaker.circuit(host).currentState(time.Now())
+}
+
+// breaker holds a circuit per host.
+type breaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (b *breaker) middleware(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*Response, error) {
+		c := b.circuit(req.URL.Host)
+		if !c.allow(time.Now()) {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.URL.Host)
+		}
+		resp, err := next.Do(req)
+		if errors.Is(err, context.Canceled) {
+			// The cancellation tells nothing about the host.
+			c.release(time.Now())
+			return resp, err
+		}
+		c.record(b.isFailure(resp, err), time.Now())
+		return resp, err
+	})
+}
+
+func (b *breaker) circuit(host string) *circuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{policy: &b.policy}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+func (b *breaker) isFailure(resp *Response, err error) bool {
+	if b.policy.IsFailure != nil {
+		return b.policy.IsFailure(resp, err)
+	}
+	if err != nil {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= 500
+}
+
+// circuit is the circuit breaker of a host.
+type circuit struct {
+	policy *BreakerPolicy
+
+	mu          sync.Mutex
+	state       CircuitState
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+	trials      int
+	successes   int
+}
+
+// currentState returns the state at now, moving from open to half open
+// once the open timeout elapsed.
+func (c *circuit) currentState(now time.Time) CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh(now)
+	return c.state
+}
+
+// allow reports whether a request can be sent.
+func (c *circuit) allow(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh(now)
+	switch c.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if c.trials >= max(c.policy.HalfOpenRequests, 1) {
+			return false
+		}
+		c.trials++
+	}
+	return true
+}
+
+// record records the result of a request allowed by allow.
+func (c *circuit) record(failure bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh(now)
+	switch c.state {
+	case CircuitHalfOpen:
+		if failure {
+			c.open(now)
+			return
+		}
+		c.successes++
+		if c.successes >= max(c.policy.HalfOpenRequests, 1) {
+			c.close(now)
+		}
+	case CircuitClosed:
+		c.requests++
+		if !failure {
+			c.consecutive = 0
+			return
+		}
+		c.failures++
+		c.consecutive++
+		if c.tripped() {
+			c.open(now)
+		}
+	}
+}
+
+// release releases a request allowed by allow without recording its
+// result, freeing its trial slot if the circuit is half open.
+func (c *circuit) release(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refresh(now)
+	if c.state == CircuitHalfOpen {
+		c.trials = max(c.trials-1, 0)
+	}
+}
+
+// tripped reports whether the failures reached a threshold.
+func (c *circuit) tripped() bool {
+	p := c.policy
+	consecutive := p.ConsecutiveFailures
+	if consecutive == 0 && p.FailureRate == 0 {
+		consecutive = defaultConsecutiveFailures
+	}
+	if consecutive > 0 && c.consecutive >= consecutive {
+		return true
+	}
+	if p.FailureRate > 0 {
+		minRequests := p.MinRequests
+		if minRequests <= 0 {
+			minRequests = defaultMinRequests
+		}
+		return c.requests >= minRequests && float64(c.failures)/float64(c.requests) >= p.FailureRate
+	}
+	return false
+}
+
+func (c *circuit) refresh(now time.Time) {
+	switch c.state {
+	case CircuitOpen:
+		timeout := c.policy.OpenTimeout
+		if timeout <= 0 {
+			timeout = defaultOpenTimeout
+		}
+		if now.Sub(c.openedAt) >= timeout {
+			c.state = CircuitHalfOpen
+			c.trials, c.successes = 0, 0
+		}
+	case CircuitClosed:
+		window := c.policy.Window
+		if window <= 0 {
+			window = defaultFailureWindow
+		}
+		if now.Sub(c.windowStart) >= window {
+			c.windowStart = now
+			c.requests, c.failures = 0, 0
+		}
+	}
+}
+
+func (c *circuit) open(now time.Time) {
+	c.state = CircuitOpen
+	c.openedAt = now
+}
+
+func (c *circuit) close(now time.Time) {
+	c.state = CircuitClosed
+	c.windowStart = now
+	c.requests, c.failures, c.consecutive = 0, 0, 0
+}
@@ -0,0 +1,201 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuit_ConsecutiveFailures(t *testing.T) {
+	now := time.Now()
+	c := &circuit{policy: &BreakerPolicy{ConsecutiveFailures: 2, OpenTimeout: time.Second, HalfOpenRequests: 2}}
+
+	assert.True(t, c.allow(now))
+	c.record(true, now)
+	assert.True(t, c.allow(now))
+	c.record(false, now)
+	assert.True(t, c.allow(now))
+	c.record(true, now)
+	assert.Equal(t, CircuitClosed, c.currentState(now))
+	assert.True(t, c.allow(now))
+	c.record(true, now)
+	assert.Equal(t, CircuitOpen, c.currentState(now))
+	assert.False(t, c.allow(now))
+
+	// Half open lets two trials through, both must succeed.
+	now = now.Add(time.Second)
+	assert.Equal(t, CircuitHalfOpen, c.currentState(now))
+	assert.True(t, c.allow(now))
+	assert.True(t, c.allow(now))
+	assert.False(t, c.allow(now))
+	c.record(false, now)
+	assert.Equal(t, CircuitHalfOpen, c.currentState(now))
+	c.record(true, now)
+	assert.Equal(t, CircuitOpen, c.currentState(now))
+
+	now = now.Add(time.Second)
+	assert.True(t, c.allow(now))
+	assert.True(t, c.allow(now))
+	c.record(false, now)
+	c.record(false, now)
+	assert.Equal(t, CircuitClosed, c.currentState(now))
+}
+
+func TestCircuit_FailureRate(t *testing.T) {
+	now := time.Now()
+	c := &circuit{policy: &BreakerPolicy{FailureRate: 0.5, MinRequests: 4, Window: time.Minute}}
+	for _, failure := range []bool{true, false, true} {
+		assert.True(t, c.allow(now))
+		c.record(failure, now)
+	}
+	assert.Equal(t, CircuitClosed, c.currentState(now))
+	c.record(false, now)
+	assert.Equal(t, CircuitClosed, c.currentState(now))
+	c.record(true, now)
+	assert.Equal(t, CircuitOpen, c.currentState(now))
+
+	// Failures are forgotten once the window elapsed.
+	c = &circuit{policy: &BreakerPolicy{FailureRate: 0.5, MinRequests: 2, Window: time.Minute}}
+	c.record(true, now)
+	c.record(false, now.Add(2*time.Minute))
+	c.record(false, now.Add(2*time.Minute))
+	assert.Equal(t, CircuitClosed, c.currentState(now.Add(2*time.Minute)))
+}
+
+func TestBreaker_IsFailure(t *testing.T) {
+	b := &breaker{}
+	assert.False(t, b.isFailure(nil, nil))
+	assert.True(t, b.isFailure(&Response{Response: &http.Response{StatusCode: 502}}, nil))
+	assert.False(t, b.isFailure(&Response{Response: &http.Response{StatusCode: 404}}, nil))
+}
+
+func TestSession_CircuitBreaker(t *testing.T) {
+	var count atomic.Int32
+	var healthy atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	s := NewSession(WithCircuitBreaker(BreakerPolicy{
+		ConsecutiveFailures: 2,
+		OpenTimeout:         50 * time.Millisecond,
+	}))
+	for i := 0; i < 2; i++ {
+		resp, err := s.Get(ts.URL)
+		assert.Nil(t, err)
+		_ = resp.Close()
+	}
+	assert.Equal(t, CircuitOpen, s.CircuitState(u.Host))
+
+	_, err := s.Get(ts.URL)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), count.Load())
+
+	healthy.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, s.CircuitState(u.Host))
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, CircuitClosed, s.CircuitState(u.Host))
+}
+
+func TestSession_CircuitBreakerIsFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	s := NewSession(
+		WithRetry(fastRetry),
+		WithCircuitBreaker(BreakerPolicy{
+			ConsecutiveFailures: 1,
+			IsFailure: func(resp *Response, err error) bool {
+				return err != nil || resp.StatusCode == http.StatusTooManyRequests
+			},
+		}),
+	)
+	// The first attempt opens the circuit, the retry is rejected and not retried.
+	_, err := s.Get(ts.URL)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestSession_CircuitBreakerCanceledTrial(t *testing.T) {
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(started)
+			<-r.Context().Done()
+		}
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	s := NewSession(WithCircuitBreaker(BreakerPolicy{ConsecutiveFailures: 1, OpenTimeout: time.Millisecond}))
+	s.breaker.circuit(u.Host).record(true, time.Now())
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, s.CircuitState(u.Host))
+
+	// The cancelled trial is neither a success nor a failure.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	req, err := s.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/slow")
+	assert.Nil(t, err)
+	_, err = s.Do(req)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, CircuitHalfOpen, s.CircuitState(u.Host))
+
+	// Its slot is released for another trial.
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, CircuitClosed, s.CircuitState(u.Host))
+}
+
+func TestSession_CircuitBreakerCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	// Cancelled requests do not reset the consecutive failures.
+	s := NewSession(WithCircuitBreaker(BreakerPolicy{ConsecutiveFailures: 2}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		req, _ := s.NewRequestWithContext(ctx, http.MethodGet, ts.URL)
+		_, err := s.Do(req)
+		assert.ErrorIs(t, err, context.Canceled)
+		resp, err := s.Get(ts.URL)
+		assert.Nil(t, err)
+		_ = resp.Close()
+	}
+	assert.Equal(t, CircuitOpen, s.CircuitState(u.Host))
+}
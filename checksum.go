@@ -0,0 +1,205 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Checksum algorithms, named as in the Digest and Repr-Digest headers.
+const (
+	SHA256 = "sha-256"
+	SHA512 = "sha-512"
+	MD5    = "md5"
+)
+
+// Checksum is the expected digest of a content.
+type Checksum struct {
+	// Algorithm is SHA256, SHA512 or MD5.
+	Algorithm string `json:"algorithm"`
+
+	// Hex is the digest in hexadecimal, in any case.
+	Hex string `json:"hex"`
+
+	// Header is the response header the checksum comes from, if any.
+	Header string `json:"header,omitempty"`
+}
+
+// SHA256Sum returns the SHA-256 Checksum of the hexadecimal digest hex.
+func SHA256Sum(hex string) Checksum {
+	return Checksum{Algorithm: SHA256, Hex: hex}
+}
+
+// SHA512Sum returns the SHA-512 Checksum of the hexadecimal digest hex.
+func SHA512Sum(hex string) Checksum {
+	return Checksum{Algorithm: SHA512, Hex: hex}
+}
+
+// MD5Sum returns the MD5 Checksum of the hexadecimal digest hex.
+func MD5Sum(hex string) Checksum {
+	return Checksum{Algorithm: MD5, Hex: hex}
+}
+
+// ChecksumError is the error of a content which does not match its Checksum.
+type ChecksumError struct {
+	Checksum
+
+	// Actual is the hexadecimal digest of the content.
+	Actual string
+}
+
+func (e *ChecksumError) Error() string {
+	from := ""
+	if e.Header != "" {
+		from = " from " + e.Header
+	}
+	return fmt.Sprintf("%s checksum mismatch: got %s, want %s%s", e.Algorithm, e.Actual, strings.ToLower(e.Hex), from)
+}
+
+// VerifyChecksum makes Session.Download and Response.SaveContent verify the
+// content against the checksums, in addition to the checksums of the
+// Digest, Repr-Digest and Content-MD5 response headers.
+func VerifyChecksum(sums ...Checksum) RequestOption {
+	return func(req *http.Request) error {
+		for _, sum := range sums {
+			if newHash(sum.Algorithm) == nil {
+				return fmt.Errorf("unsupported checksum algorithm %q", sum.Algorithm)
+			}
+		}
+		setTransfer(req, func(c *transferConfig) {
+			c.checksums = append(c.checksums[:len(c.checksums):len(c.checksums)], sums...)
+		})
+		return nil
+	}
+}
+
+// verifier computes the digests of the content written to it.
+type verifier struct {
+	sums   []Checksum
+	hashes []hash.Hash
+}
+
+func newVerifier(sums []Checksum) (*verifier, error) {
+	v := &verifier{sums: sums}
+	for _, sum := range sums {
+		h := newHash(sum.Algorithm)
+		if h == nil {
+			return nil, fmt.Errorf("unsupported checksum algorithm %q", sum.Algorithm)
+		}
+		v.hashes = append(v.hashes, h)
+	}
+	return v, nil
+}
+
+func (v *verifier) Write(p []byte) (int, error) {
+	for _, h := range v.hashes {
+		h.Write(p)
+	}
+	return len(p), nil
+}
+
+// verify returns a *ChecksumError if a digest does not match its checksum.
+func (v *verifier) verify() error {
+	for i, sum := range v.sums {
+		actual := hex.EncodeToString(v.hashes[i].Sum(nil))
+		if !strings.EqualFold(actual, sum.Hex) {
+			return &ChecksumError{Checksum: sum, Actual: actual}
+		}
+	}
+	return nil
+}
+
+// verifyFile verifies the content of the file name against sums.
+func verifyFile(name string, sums []Checksum) error {
+	if len(sums) == 0 {
+		return nil
+	}
+	v, err := newVerifier(sums)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(v, f); err != nil {
+		return err
+	}
+	return v.verify()
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch strings.ToLower(algorithm) {
+	case SHA256, "sha256":
+		return sha256.New()
+	case SHA512, "sha512":
+		return sha512.New()
+	case MD5:
+		return md5.New()
+	}
+	return nil
+}
+
+// headerChecksums returns the checksums of the full content of resp from
+// its Repr-Digest, Digest and Content-MD5 headers, with supported algorithms.
+// Partial and transparently decompressed responses have none.
+func headerChecksums(resp *http.Response) []Checksum {
+	if resp.StatusCode != http.StatusOK || resp.Uncompressed {
+		return nil
+	}
+	var sums []Checksum
+	add := func(header, algorithm, b64 string) {
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil || newHash(algorithm) == nil {
+			return
+		}
+		sums = append(sums, Checksum{
+			Algorithm: strings.ToLower(algorithm),
+			Hex:       hex.EncodeToString(b),
+			Header:    header,
+		})
+	}
+	// Repr-Digest: sha-256=:base64:, sha-512=:base64:
+	for _, v := range resp.Header.Values("Repr-Digest") {
+		for _, item := range strings.Split(v, ",") {
+			alg, val, ok := strings.Cut(strings.TrimSpace(item), "=")
+			if ok && len(val) > 1 && val[0] == ':' && val[len(val)-1] == ':' {
+				add("Repr-Digest", alg, val[1:len(val)-1])
+			}
+		}
+	}
+	// Digest: SHA-256=base64, MD5=base64
+	for _, v := range resp.Header.Values("Digest") {
+		for _, item := range strings.Split(v, ",") {
+			if alg, val, ok := strings.Cut(strings.TrimSpace(item), "="); ok {
+				add("Digest", alg, val)
+			}
+		}
+	}
+	if v := resp.Header.Get("Content-MD5"); v != "" {
+		add("Content-MD5", MD5, v)
+	}
+	return sums
+}
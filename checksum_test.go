@@ -0,0 +1,126 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHeaderChecksums(t *testing.T) {
+	content := []byte("hello world")
+	s256 := sha256.Sum256(content)
+	s512 := sha512.Sum512(content)
+	m5 := md5.Sum(content)
+	b64 := base64.StdEncoding.EncodeToString
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("Repr-Digest", "sha-256=:"+b64(s256[:])+":, unixsum=:MTIz:")
+	resp.Header.Set("Digest", "SHA-512="+b64(s512[:])+", crc32c=abc")
+	resp.Header.Set("Content-MD5", b64(m5[:]))
+	assert.Equal(t, []Checksum{
+		{Algorithm: SHA256, Hex: hex.EncodeToString(s256[:]), Header: "Repr-Digest"},
+		{Algorithm: SHA512, Hex: hex.EncodeToString(s512[:]), Header: "Digest"},
+		{Algorithm: MD5, Hex: hex.EncodeToString(m5[:]), Header: "Content-MD5"},
+	}, headerChecksums(resp))
+
+	resp.StatusCode = http.StatusPartialContent
+	assert.Nil(t, headerChecksums(resp))
+}
+
+func TestResponse_SaveContentChecksum(t *testing.T) {
+	content := "hello world"
+	sum := sha256Hex([]byte(content))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bad" {
+			w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(make([]byte, md5.Size)))
+		}
+		w.Write([]byte(content))
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+
+	resp, _ := Get(ts.URL)
+	name := filepath.Join(dir, "ok")
+	assert.Nil(t, resp.SaveContent(name, SHA256Sum(strings.ToUpper(sum))))
+	assert.FileExists(t, name)
+
+	// Checksum of the request option, with a cached body.
+	resp, _ = Get(ts.URL, VerifyChecksum(SHA256Sum(sha256Hex(nil))))
+	resp.Content()
+	name = filepath.Join(dir, "option")
+	err := resp.SaveContent(name)
+	var sumErr *ChecksumError
+	assert.True(t, errors.As(err, &sumErr))
+	assert.Equal(t, sum, sumErr.Actual)
+	assert.NoFileExists(t, name)
+
+	resp, _ = Get(ts.URL + "/bad")
+	name = filepath.Join(dir, "bad")
+	err = resp.SaveContent(name)
+	assert.True(t, errors.As(err, &sumErr))
+	assert.Equal(t, "Content-MD5", sumErr.Header)
+	assert.Contains(t, err.Error(), "md5 checksum mismatch")
+	assert.NoFileExists(t, name)
+
+	_, err = Get(ts.URL, VerifyChecksum(Checksum{Algorithm: "crc32"}))
+	assert.NotNil(t, err)
+}
+
+func TestSession_DownloadChecksum(t *testing.T) {
+	sum := sha256Hex(downloadContent)
+	dir := t.TempDir()
+
+	// Resumed download.
+	ds := newDownloadServer(1)
+	defer ds.Close()
+	path := filepath.Join(dir, "resumed")
+	assert.Nil(t, NewSession().Download(ds.URL, path, VerifyChecksum(SHA256Sum(sum))))
+	assert.FileExists(t, path)
+
+	path = filepath.Join(dir, "mismatch")
+	err := NewSession().Download(ds.URL, path, VerifyChecksum(MD5Sum("00")))
+	var sumErr *ChecksumError
+	assert.True(t, errors.As(err, &sumErr))
+	assert.NoFileExists(t, path)
+	assert.NoFileExists(t, path+partSuffix)
+	assert.NoFileExists(t, path+partSuffix+metaSuffix)
+
+	// Segmented download, with the header checksum.
+	ss := newSegmentServer()
+	defer ss.Close()
+	path = filepath.Join(dir, "segments")
+	assert.Nil(t, NewSession().Download(ss.URL, path, Segments(3), VerifyChecksum(SHA256Sum(sum))))
+	assert.FileExists(t, path)
+
+	path = filepath.Join(dir, "segments-mismatch")
+	err = NewSession().Download(ss.URL, path, Segments(3), VerifyChecksum(SHA512Sum("00")))
+	assert.True(t, errors.As(err, &sumErr))
+	assert.NoFileExists(t, path+partSuffix)
+}
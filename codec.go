@@ -0,0 +1,235 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrNoCodec is returned when no codec is registered for a media type.
+var ErrNoCodec = errors.New("no codec for media type")
+
+// Codec encodes and decodes the bodies of a media type.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Built-in codecs, registered for their media type.
+var (
+	// JSONCodec encodes with encoding/json, for application/json and the
+	// media types with the +json suffix.
+	JSONCodec Codec = jsonCodec{}
+
+	// XMLCodec encodes with encoding/xml, for application/xml, text/xml and
+	// the media types with the +xml suffix.
+	XMLCodec Codec = xmlCodec{}
+
+	// FormCodec encodes url.Values, maps and structs as with EncodeValues,
+	// and decodes to *url.Values or *map[string]string, for
+	// application/x-www-form-urlencoded.
+	FormCodec Codec = formCodec{}
+
+	// TextCodec encodes strings, byte slices and encoding.TextMarshaler,
+	// and decodes to *string, *[]byte or encoding.TextUnmarshaler, for
+	// text/plain and the other text media types.
+	TextCodec Codec = textCodec{}
+)
+
+var codecs = struct {
+	sync.RWMutex
+	m map[string]Codec
+}{m: map[string]Codec{
+	AppJSON:           JSONCodec,
+	"application/xml": XMLCodec,
+	"text/xml":        XMLCodec,
+	AppFormURLEncoded: FormCodec,
+	"text/plain":      TextCodec,
+}}
+
+// RegisterCodec registers c for the media type mediaType, such as
+// "application/yaml", for all the sessions. See WithCodec to register
+// a codec for a session only.
+func RegisterCodec(mediaType string, c Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.m[mediaType] = c
+}
+
+// WithCodec registers c for the media type mediaType in the session,
+// taking precedence over the codecs registered with RegisterCodec.
+func WithCodec(mediaType string, c Codec) SessionOption {
+	return func(s *Session) {
+		if s.codecs == nil {
+			s.codecs = make(map[string]Codec)
+		}
+		s.codecs[mediaType] = c
+	}
+}
+
+type codecsKey struct{}
+
+// withCodecs returns ctx carrying the codecs of a session.
+func withCodecs(ctx context.Context, m map[string]Codec) context.Context {
+	if len(m) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, codecsKey{}, m)
+}
+
+// lookupCodec returns the codec of the media type of contentType, from
+// the session codecs m, or else the registered codecs. Media types with
+// the +json and +xml suffixes fall back to the JSON and XML codecs, and
+// text media types to the text codec.
+func lookupCodec(m map[string]Codec, contentType string) (Codec, error) {
+	mt := mediaType(contentType)
+	candidates := []string{mt}
+	switch {
+	case strings.HasSuffix(mt, "+json"):
+		candidates = append(candidates, AppJSON)
+	case strings.HasSuffix(mt, "+xml"):
+		candidates = append(candidates, "application/xml")
+	case strings.HasPrefix(mt, "text/"):
+		candidates = append(candidates, "text/plain")
+	}
+	for _, name := range candidates {
+		if c, ok := m[name]; ok {
+			return c, nil
+		}
+		codecs.RLock()
+		c, ok := codecs.m[name]
+		codecs.RUnlock()
+		if ok {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrNoCodec, contentType)
+}
+
+// Encode encodes v with the codec of mediaType, and sets it as request
+// body with the Content-Type mediaType, which may have parameters such as
+// "text/plain; charset=utf-8".
+func Encode(mediaType string, v any) RequestOption {
+	return func(req *http.Request) error {
+		m, _ := req.Context().Value(codecsKey{}).(map[string]Codec)
+		c, err := lookupCodec(m, mediaType)
+		if err != nil {
+			return err
+		}
+		b, err := c.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if err := setRequestBody(req, bytes.NewReader(b)); err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", mediaType)
+		return nil
+	}
+}
+
+// Decode reads body of response and decodes it to v, with the codec of
+// the Content-Type of the response.
+func (r *Response) Decode(v any) error {
+	c, err := lookupCodec(r.codecs, r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	content, err := r.Content()
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(content, v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v any) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
+
+type formCodec struct{}
+
+func (formCodec) Marshal(v any) ([]byte, error) {
+	values, err := EncodeValues(v)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(values.Encode()), nil
+}
+
+func (formCodec) Unmarshal(data []byte, v any) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case *url.Values:
+		*v = values
+	case *map[string][]string:
+		*v = values
+	case *map[string]string:
+		*v = make(map[string]string, len(values))
+		for k := range values {
+			(*v)[k] = values.Get(k)
+		}
+	default:
+		return fmt.Errorf("form: cannot decode to %T", v)
+	}
+	return nil
+}
+
+type textCodec struct{}
+
+func (textCodec) Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	case fmt.Stringer:
+		return []byte(v.String()), nil
+	}
+	return nil, fmt.Errorf("text: cannot encode %T", v)
+}
+
+func (textCodec) Unmarshal(data []byte, v any) error {
+	switch v := v.(type) {
+	case *string:
+		*v = string(data)
+	case *[]byte:
+		*v = bytes.Clone(data)
+	case encoding.TextUnmarshaler:
+		return v.UnmarshalText(data)
+	default:
+		return fmt.Errorf("text: cannot decode to %T", v)
+	}
+	return nil
+}
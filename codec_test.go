@@ -0,0 +1,137 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// kvCodec encodes map[string]string as "key: value" lines.
+type kvCodec struct{}
+
+func (kvCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(map[string]string)
+	if !ok {
+		return nil, fmt.Errorf("kv: cannot encode %T", v)
+	}
+	var b strings.Builder
+	for k, v := range m {
+		b.WriteString(k + ": " + v + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+func (kvCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(*map[string]string)
+	if !ok {
+		return fmt.Errorf("kv: cannot decode to %T", v)
+	}
+	*m = map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		k, v, _ := strings.Cut(line, ": ")
+		(*m)[k] = v
+	}
+	return nil
+}
+
+// codecServer echoes the body of the requests, with their Content-Type.
+func codecServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+}
+
+func TestCodecs(t *testing.T) {
+	ts := codecServer()
+	defer ts.Close()
+
+	type item struct {
+		Name string `json:"name" xml:"name" url:"name"`
+	}
+	var testcases = []struct {
+		mediaType string
+		in        any
+		out       any
+		expected  any
+	}{
+		{AppJSON, item{"a"}, &item{}, &item{"a"}},
+		{"application/vnd.api+json; charset=utf-8", item{"a"}, &item{}, &item{"a"}},
+		{"application/xml", item{"a"}, &item{}, &item{"a"}},
+		{"application/atom+xml", item{"a"}, &item{}, &item{"a"}},
+		{AppFormURLEncoded, item{"a"}, &url.Values{}, &url.Values{"name": {"a"}}},
+		{AppFormURLEncoded, url.Values{"a": {"1"}}, &map[string]string{}, &map[string]string{"a": "1"}},
+		{"text/plain; charset=utf-8", "hello", new(string), ptr("hello")},
+		{"text/csv", []byte("a,b"), new([]byte), ptr([]byte("a,b"))},
+	}
+	for _, tc := range testcases {
+		resp, err := Post(ts.URL, Encode(tc.mediaType, tc.in))
+		assert.Nil(t, err, tc.mediaType)
+		assert.Nil(t, resp.Decode(tc.out), tc.mediaType)
+		assert.Equal(t, tc.expected, tc.out, tc.mediaType)
+	}
+
+	_, err := Post(ts.URL, Encode("application/x-kv", M{"a": "1"}))
+	assert.True(t, errors.Is(err, ErrNoCodec))
+	resp, err := Post(ts.URL, Body(strings.NewReader("a")), Headers(M{"Content-Type": "image/png"}))
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(resp.Decode(new(string)), ErrNoCodec))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestWithCodec(t *testing.T) {
+	ts := codecServer()
+	defer ts.Close()
+
+	s := NewSession(WithCodec("application/x-kv", kvCodec{}))
+	resp, err := s.Post(ts.URL, Encode("application/x-kv", M{"a": "1"}))
+	assert.Nil(t, err)
+	var m map[string]string
+	assert.Nil(t, resp.Decode(&m))
+	assert.Equal(t, M{"a": "1"}, m)
+
+	// Only registered for the session.
+	_, err = Post(ts.URL, Encode("application/x-kv", M{"a": "1"}))
+	assert.True(t, errors.Is(err, ErrNoCodec))
+
+	// Session codecs take precedence over the registered ones.
+	s = NewSession(WithCodec(AppJSON, kvCodec{}))
+	resp, err = s.Post(ts.URL, Encode(AppJSON, M{"a": "1"}))
+	assert.Nil(t, err)
+	txt, _ := resp.Text()
+	assert.Equal(t, "a: 1\n", txt)
+}
+
+func TestRegisterCodec(t *testing.T) {
+	ts := codecServer()
+	defer ts.Close()
+
+	RegisterCodec("application/x-kv-global", kvCodec{})
+	resp, err := Post(ts.URL, Encode("application/x-kv-global", M{"a": "1"}))
+	assert.Nil(t, err)
+	var m map[string]string
+	assert.Nil(t, resp.Decode(&m))
+	assert.Equal(t, M{"a": "1"}, m)
+}
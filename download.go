@@ -0,0 +1,315 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// partSuffix is the suffix of partial downloads.
+	partSuffix = ".part"
+	// metaSuffix is the suffix of the validators file of a partial download.
+	metaSuffix = ".meta"
+)
+
+// errRestart asks to download again from the beginning.
+var errRestart = errors.New("download must restart")
+
+// partMeta records the validators of a partial download,
+// in a file next to it, for resuming it later.
+type partMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// Checksums are the checksums of the response headers.
+	Checksums []Checksum `json:"checksums,omitempty"`
+}
+
+// interruptedError is an error sending the request or reading the response,
+// after which a download can resume.
+type interruptedError struct {
+	err error
+}
+
+func (e *interruptedError) Error() string { return e.err.Error() }
+func (e *interruptedError) Unwrap() error { return e.err }
+
+// Download downloads url to the file path, see DownloadWithContext.
+func (s *Session) Download(url, path string, opts ...RequestOption) error {
+	return s.DownloadWithContext(context.Background(), url, path, opts...)
+}
+
+// DownloadWithContext downloads url to the file path.
+//
+// The content is written to path+".part", and renamed to path once complete.
+// When the transfer is interrupted, the download resumes from the end of the
+// partial file with a Range request, up to MaxResumes times. The If-Range
+// header, with the ETag or Last-Modified of the first response, makes sure
+// the content did not change meanwhile. A partial file left by a previous
+// call is resumed the same way. If the server ignores the range, or the
+// content has no validator, the download restarts from the beginning.
+//
+// With the Segments option, the content is fetched in parallel segments
+// written into a preallocated file instead, each one resumed individually.
+// It falls back to a sequential download if a segment cannot be fetched with
+// a range of the same content. Segmented downloads cannot be resumed by a
+// later call.
+//
+// The content is verified against the checksums of the VerifyChecksum option
+// and of the Digest, Repr-Digest and Content-MD5 response headers. On
+// mismatch, the partial file is removed and a *ChecksumError returned.
+func (s *Session) DownloadWithContext(ctx context.Context, url, path string, opts ...RequestOption) error {
+	part := path + partSuffix
+	err := s.downloadSegments(ctx, url, part, opts)
+	if errors.Is(err, errNoRanges) || errors.Is(err, errRestart) {
+		err = s.downloadSequential(ctx, url, part, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(part, path); err != nil {
+		return err
+	}
+	_ = os.Remove(part + metaSuffix)
+	return nil
+}
+
+// downloadSequential downloads url to the file part, resuming it when
+// interrupted.
+func (s *Session) downloadSequential(ctx context.Context, url, part string, opts []RequestOption) error {
+	var p *progress
+	for attempt := 0; ; attempt++ {
+		req, err := s.NewRequestWithContext(ctx, http.MethodGet, url, opts...)
+		if err != nil {
+			return err
+		}
+		cfg := transferOf(req)
+		if attempt == 0 {
+			p = newProgress(cfg.progress, cfg.progressInterval, -1)
+		}
+
+		err = s.downloadPart(req, part, cfg.checksums, p)
+		if err == nil {
+			p.finish()
+			return nil
+		}
+		if errors.Is(err, errRestart) {
+			removePart(part)
+			continue
+		}
+		var ierr *interruptedError
+		if !errors.As(err, &ierr) || ctx.Err() != nil || attempt >= cfg.maxResumes {
+			return err
+		}
+		if err := sleep(ctx, (&RetryPolicy{}).backoff(attempt+1)); err != nil {
+			return err
+		}
+	}
+}
+
+// downloadPart sends req, resuming the partial file part if possible,
+// and writes the response body to it, reporting the progress to p. The
+// complete content is verified against sums and the checksums of the
+// response headers.
+func (s *Session) downloadPart(req *http.Request, part string, sums []Checksum, p *progress) error {
+	offset, meta := partialDownload(part, req.URL.String())
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", meta.validator())
+	}
+	resp, err := s.Do(req)
+	if err != nil {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return err
+		}
+		return &interruptedError{err}
+	}
+	copied := false
+	defer func() {
+		// Only drain the body of a completed copy, to reuse the connection.
+		if copied {
+			_ = resp.Close()
+			return
+		}
+		_ = resp.Body.Close()
+	}()
+
+	flag := os.O_RDWR | os.O_CREATE
+	switch {
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		start, _, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return errRestart
+		}
+		p.reset(offset, size)
+	case offset > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file may be complete already.
+		if _, _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size == offset {
+			p.reset(offset, size)
+			return verifyPart(part, verifyFile(part, append(sums, meta.Checksums...)))
+		}
+		return errRestart
+	case resp.StatusCode == http.StatusOK:
+		// Download from the beginning, the server ignored the range if any.
+		offset = 0
+		flag |= os.O_TRUNC
+		p.reset(0, resp.ContentLength)
+		meta = partMeta{
+			URL:          req.URL.String(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Checksums:    headerChecksums(resp.Response),
+		}
+		if err := meta.save(part + metaSuffix); err != nil {
+			return err
+		}
+	default:
+		if err := resp.RaiseForStatus(); err != nil {
+			return err
+		}
+		return fmt.Errorf("download: unexpected status %q", resp.Status)
+	}
+
+	v, err := newVerifier(append(sums, meta.Checksums...))
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(part, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	// Hash the partial file, and append to it.
+	if _, err := io.CopyN(v, f, offset); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := copyBody(io.MultiWriter(f, v, p), resp.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	copied = true
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return verifyPart(part, v.verify())
+}
+
+// verifyPart removes the partial file part if err is a *ChecksumError.
+func verifyPart(part string, err error) error {
+	var sumErr *ChecksumError
+	if errors.As(err, &sumErr) {
+		removePart(part)
+	}
+	return err
+}
+
+// copyBody copies body to w, wrapping read errors in *interruptedError.
+func copyBody(w io.Writer, body io.Reader) error {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &interruptedError{err}
+		}
+	}
+}
+
+// partialDownload returns the size of the partial file part of url which
+// can be resumed, and its validators.
+func partialDownload(part, url string) (int64, partMeta) {
+	var meta partMeta
+	b, err := os.ReadFile(part + metaSuffix)
+	if err != nil || json.Unmarshal(b, &meta) != nil || meta.URL != url || meta.validator() == "" {
+		return 0, partMeta{}
+	}
+	fi, err := os.Stat(part)
+	if err != nil {
+		return 0, partMeta{}
+	}
+	return fi.Size(), meta
+}
+
+// validator returns the value of the If-Range header, empty if there is none.
+// Weak ETags cannot be used in If-Range.
+func (m partMeta) validator() string {
+	if m.ETag != "" && !strings.HasPrefix(m.ETag, "W/") {
+		return m.ETag
+	}
+	return m.LastModified
+}
+
+func (m partMeta) save(filename string) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0o644)
+}
+
+func removePart(part string) {
+	_ = os.Remove(part)
+	_ = os.Remove(part + metaSuffix)
+}
+
+// parseContentRange parses a Content-Range header such as "bytes 0-99/1000".
+// The size is -1 if unknown, and start and end are -1 for "bytes */1000".
+func parseContentRange(v string) (start, end, size int64, ok bool) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, 0, 0, false
+	}
+	r, total, found := strings.Cut(v, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+	size = -1
+	if total != "*" {
+		n, err := strconv.ParseInt(total, 10, 64)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		size = n
+	}
+	if r == "*" {
+		return -1, -1, size, true
+	}
+	first, last, found := strings.Cut(r, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	start, err1 := strconv.ParseInt(first, 10, 64)
+	end, err2 := strconv.ParseInt(last, 10, 64)
+	if err1 != nil || err2 != nil || start > end {
+		return 0, 0, 0, false
+	}
+	return start, end, size, true
+}
@@ -0,0 +1,196 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var downloadContent = bytes.Repeat([]byte("0123456789abcdef"), 64<<10)
+
+// downloadServer serves downloadContent, aborting the first n transfers
+// half way. It records the Range and If-Range headers received.
+type downloadServer struct {
+	*httptest.Server
+	etag        string
+	ignoreRange bool
+
+	mu       sync.Mutex
+	failures int
+	ranges   []string
+	ifRanges []string
+}
+
+func newDownloadServer(failures int) *downloadServer {
+	ds := &downloadServer{etag: `"v1"`, failures: failures}
+	ds.Server = httptest.NewServer(http.HandlerFunc(ds.serve))
+	return ds
+}
+
+func (ds *downloadServer) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/missing" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	ds.mu.Lock()
+	ds.ranges = append(ds.ranges, r.Header.Get("Range"))
+	ds.ifRanges = append(ds.ifRanges, r.Header.Get("If-Range"))
+	fail := ds.failures > 0
+	ds.failures--
+	ds.mu.Unlock()
+
+	w.Header().Set("ETag", ds.etag)
+	if ds.ignoreRange {
+		r.Header.Del("Range")
+	}
+	if fail {
+		w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+		w.Write(downloadContent[:len(downloadContent)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(downloadContent))
+}
+
+func TestSession_Download(t *testing.T) {
+	ds := newDownloadServer(1)
+	defer ds.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ds.URL, path))
+
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+	assert.Equal(t, []string{"", "bytes=" + strconv.Itoa(len(downloadContent)/2) + "-"}, ds.ranges)
+	assert.Equal(t, []string{"", `"v1"`}, ds.ifRanges)
+	assert.NoFileExists(t, path+partSuffix)
+	assert.NoFileExists(t, path+partSuffix+metaSuffix)
+}
+
+func TestSession_DownloadIgnoredRange(t *testing.T) {
+	ds := newDownloadServer(1)
+	ds.ignoreRange = true
+	defer ds.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ds.URL, path))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+	assert.Equal(t, 2, len(ds.ranges))
+}
+
+func TestSession_DownloadMaxResumes(t *testing.T) {
+	ds := newDownloadServer(3)
+	defer ds.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	err := NewSession().Download(ds.URL, path, MaxResumes(1))
+	assert.NotNil(t, err)
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, path+partSuffix)
+
+	// A later call resumes the partial file.
+	assert.Nil(t, NewSession().Download(ds.URL, path))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+}
+
+func TestSession_DownloadPartialFile(t *testing.T) {
+	ds := newDownloadServer(0)
+	defer ds.Close()
+	dir := t.TempDir()
+
+	// The content changed since the partial download.
+	path := filepath.Join(dir, "changed")
+	assert.Nil(t, os.WriteFile(path+partSuffix, []byte("stale content"), 0o644))
+	assert.Nil(t, partMeta{URL: ds.URL, ETag: `"v0"`}.save(path+partSuffix+metaSuffix))
+	assert.Nil(t, NewSession().Download(ds.URL, path))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+
+	// The partial download is complete already.
+	path = filepath.Join(dir, "complete")
+	assert.Nil(t, os.WriteFile(path+partSuffix, downloadContent, 0o644))
+	assert.Nil(t, partMeta{URL: ds.URL, ETag: `"v1"`}.save(path+partSuffix+metaSuffix))
+	assert.Nil(t, NewSession().Download(ds.URL, path))
+	b, _ = os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+
+	var httpErr *HTTPError
+	err := NewSession().Download(ds.URL+"/missing", filepath.Join(dir, "missing"))
+	assert.True(t, errors.As(err, &httpErr))
+}
+
+func TestSession_DownloadRestartUnread(t *testing.T) {
+	// The range response starts at the wrong offset. Its body must not be
+	// read before restarting the download.
+	var unwanted atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("Range") == "" {
+			w.Write(downloadContent)
+			return
+		}
+		w.Header().Set("Content-Range", "bytes 0-99/"+strconv.Itoa(len(downloadContent)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			unwanted.Add(1)
+			w.Write(downloadContent)
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(path+partSuffix, downloadContent[:10], 0o644))
+	assert.Nil(t, partMeta{URL: ts.URL, ETag: `"v1"`}.save(path+partSuffix+metaSuffix))
+	assert.Nil(t, NewSession().Download(ts.URL, path))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+	assert.Equal(t, int32(0), unwanted.Load())
+}
+
+func TestParseContentRange(t *testing.T) {
+	var testcases = []struct {
+		v                string
+		start, end, size int64
+		ok               bool
+	}{
+		{"bytes 0-99/1000", 0, 99, 1000, true},
+		{"bytes 100-199/*", 100, 199, -1, true},
+		{"bytes */1000", -1, -1, 1000, true},
+		{"bytes 10-5/1000", 0, 0, 0, false},
+		{"items 0-1/2", 0, 0, 0, false},
+		{"bytes 0-99", 0, 0, 0, false},
+	}
+	for _, tc := range testcases {
+		start, end, size, ok := parseContentRange(tc.v)
+		assert.Equal(t, tc.ok, ok, tc.v)
+		assert.Equal(t, []int64{tc.start, tc.end, tc.size}, []int64{start, end, size}, tc.v)
+	}
+}
@@ -0,0 +1,74 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// MaxErrorBodySnippet is the maximum number of body bytes kept by HTTPError.
+const MaxErrorBodySnippet = 4096
+
+// maxErrorBodyDecode is the maximum number of body bytes decoded into HTTPError.Decoded.
+const maxErrorBodyDecode = 1 << 20
+
+// HTTPError is the error of a response with a status code which is not OK.
+type HTTPError struct {
+	// StatusCode and Status are the status of the response, e.g. 404 and "404 Not Found".
+	StatusCode int
+	Status     string
+
+	// Method and URL are the method and the final URL of the request.
+	Method string
+	URL    string
+
+	// Header is the header of the response.
+	Header http.Header
+
+	// Body is the beginning of the response body,
+	// at most MaxErrorBodySnippet bytes.
+	Body []byte
+
+	// Decoded is the decoded JSON body: a value of the type registered
+	// WithErrorBody, or else a *ProblemDetails for application/problem+json
+	// responses. It is nil if the body was not decoded.
+	Decoded any
+}
+
+func (e *HTTPError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if e.Method == "" && e.URL == "" {
+		return "http error: " + status
+	}
+	return fmt.Sprintf("%s %s: %s", e.Method, e.URL, status)
+}
+
+// Unwrap returns the decoded body if it is an error,
+// so that errors.As can find it, as with *ProblemDetails.
+func (e *HTTPError) Unwrap() error {
+	if err, ok := e.Decoded.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Problem returns the decoded RFC 7807 problem details, if any.
+func (e *HTTPError) Problem() (*ProblemDetails, bool) {
+	p, ok := e.Decoded.(*ProblemDetails)
+	return p, ok
+}
@@ -0,0 +1,183 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// StreamJSON decodes the JSON body of the response into v
+// without reading the whole body in memory first.
+//
+// The Content-Type is checked like in JSON. Unless the body was already
+// cached by Content, it is consumed and cannot be read again.
+func (r *Response) StreamJSON(v interface{}) error {
+	if err := r.checkJSON(); err != nil {
+		return err
+	}
+	body, err := r.stream()
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(body).Decode(v)
+}
+
+// JSONArray iterates over the elements of a JSON array in the body of r,
+// decoding them one at a time into T.
+//
+// The array is the top-level value of the body, or the value found by
+// following the object keys of path, e.g. JSONArray[Item](r, "data", "items")
+// for {"data": {"items": [...]}}. Members before the array are skipped
+// without being decoded, members after it are not read.
+//
+// The iteration stops after yielding an error. Unless the body was already
+// cached by Content, it is consumed and cannot be read again.
+func JSONArray[T any](r *Response, path ...string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := r.checkJSON(); err != nil {
+			yield(zero, err)
+			return
+		}
+		body, err := r.stream()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		dec := json.NewDecoder(body)
+		if err := seekJSONPath(dec, path); err != nil {
+			yield(zero, err)
+			return
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			yield(zero, err)
+			return
+		}
+		for i := 0; dec.More(); i++ {
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				yield(zero, fmt.Errorf("json array element %d: %w", i, err))
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// stream returns a reader of the body for decoding it on the fly.
+// The body is consumed unless it was cached.
+func (r *Response) stream() (io.Reader, error) {
+	if r.cached {
+		return bytes.NewReader(r.content), nil
+	}
+	if r.consumed {
+		return nil, ErrBodyConsumed
+	}
+	if r.Body == nil {
+		return bytes.NewReader(nil), nil
+	}
+	r.consumed = true
+	var body io.Reader = r.Body
+	if r.MaxBodySize > 0 {
+		body = &maxBytesReader{r: body, left: r.MaxBodySize}
+	}
+	return body, nil
+}
+
+// maxBytesReader reads from r and fails with ErrBodyTooLarge after left bytes.
+type maxBytesReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > m.left+1 {
+		p = p[:m.left+1]
+	}
+	n, err := m.r.Read(p)
+	if int64(n) > m.left {
+		n = int(m.left)
+		m.left = 0
+		return n, ErrBodyTooLarge
+	}
+	m.left -= int64(n)
+	return n, err
+}
+
+// seekJSONPath moves dec to the value found by following the object keys of path.
+func seekJSONPath(dec *json.Decoder, path []string) error {
+	for _, key := range path {
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for {
+			if !dec.More() {
+				return fmt.Errorf("json key %q not found", key)
+			}
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if tok == key {
+				break
+			}
+			if err := skipJSONValue(dec); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipJSONValue skips the next value of dec, token by token.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// expectDelim reads the next token of dec, which must be the delimiter d.
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("json: expected %q, got %v", d, tok)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func jsonResponse(body string) *Response {
+	return NewResponse(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {AppJSON}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	})
+}
+
+func collectJSONArray[T any](r *Response, path ...string) ([]T, error) {
+	var items []T
+	for v, err := range JSONArray[T](r, path...) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func TestResponse_StreamJSON(t *testing.T) {
+	resp := jsonResponse(`{"name": "foo", "age": 10}`)
+	var data respData
+	assert.Nil(t, resp.StreamJSON(&data))
+	assert.Equal(t, respData{Name: "foo", Age: 10}, data)
+	assert.ErrorIs(t, resp.StreamJSON(&data), ErrBodyConsumed)
+
+	// A cached body can be decoded repeatedly.
+	resp = jsonResponse(`{"name": "foo", "age": 10}`)
+	_, _ = resp.Content()
+	assert.Nil(t, resp.StreamJSON(&data))
+	assert.Nil(t, resp.StreamJSON(&data))
+
+	resp = jsonResponse(`{"name": "foo", "age": 10}`)
+	resp.MaxBodySize = 5
+	assert.ErrorIs(t, resp.StreamJSON(&data), ErrBodyTooLarge)
+
+	resp = jsonResponse(`{}`)
+	resp.Header.Set("Content-Type", "text/plain")
+	assert.ErrorIs(t, resp.StreamJSON(&data), ErrNotJSONContent)
+}
+
+func TestJSONArray(t *testing.T) {
+	items, err := collectJSONArray[respData](jsonResponse(`[{"name": "a", "age": 1}, {"name": "b", "age": 2}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []respData{{"a", 1}, {"b", 2}}, items)
+
+	body := `{
+		"meta": {"skip": [1, {"x": [2]}], "y": "z"},
+		"data": {"total": 3, "items": [1, 2, 3], "next": null}
+	}`
+	ints, err := collectJSONArray[int](jsonResponse(body), "data", "items")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ints)
+
+	_, err = collectJSONArray[int](jsonResponse(body), "data", "missing")
+	assert.EqualError(t, err, `json key "missing" not found`)
+
+	_, err = collectJSONArray[int](jsonResponse(body), "data", "total")
+	assert.NotNil(t, err)
+
+	ints, err = collectJSONArray[int](jsonResponse(`[1, 2, "three", 4]`))
+	assert.ErrorContains(t, err, "json array element 2")
+	assert.Equal(t, []int{1, 2}, ints)
+
+	// Stop early.
+	for v, err := range JSONArray[int](jsonResponse(`[1, 2, 3]`)) {
+		assert.Nil(t, err)
+		assert.Equal(t, 1, v)
+		break
+	}
+}
@@ -0,0 +1,198 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Rate is the rate of a token bucket limiting requests.
+type Rate struct {
+	// PerSecond is the number of requests allowed per second.
+	// Zero or less means no limit.
+	PerSecond float64
+
+	// Burst is the number of requests which can be sent at once.
+	// Values less than 1 are treated as 1.
+	Burst int
+}
+
+// LimiterStats are the statistics of the rate limiter of a Session.
+type LimiterStats struct {
+	// Requests is the number of requests which went through the limiter.
+	Requests int64
+
+	// Delayed is the number of requests which had to wait for a token.
+	Delayed int64
+
+	// TotalWait is the total time requests waited for a token.
+	TotalWait time.Duration
+
+	// MaxWait is the longest time a request waited for a token.
+	MaxWait time.Duration
+}
+
+// WithRateLimit limits the rate of all requests sent by the session.
+func WithRateLimit(r Rate) SessionOption {
+	return func(s *Session) {
+		s.rateLimiter().global = newTokenBucket(r)
+	}
+}
+
+// WithHostRateLimit limits the rate of requests sent by the session to host.
+// The host may include a port, in which case only requests to that port are limited.
+// Requests to a host are limited by both its own rate and the rate of WithRateLimit.
+func WithHostRateLimit(host string, r Rate) SessionOption {
+	return func(s *Session) {
+		s.rateLimiter().rates[host] = r
+	}
+}
+
+// LimiterStats returns the statistics of the rate limiter of the session.
+func (s *Session) LimiterStats() LimiterStats {
+	if s.limiter == nil {
+		return LimiterStats{}
+	}
+	s.limiter.mu.Lock()
+	defer s.limiter.mu.Unlock()
+	return s.limiter.stats
+}
+
+func (s *Session) rateLimiter() *limiter {
+	if s.limiter == nil {
+		s.limiter = &limiter{
+			rates:   make(map[string]Rate),
+			buckets: make(map[string]*tokenBucket),
+		}
+	}
+	return s.limiter
+}
+
+// limiter limits requests with a global token bucket and one per host.
+type limiter struct {
+	global *tokenBucket
+	rates  map[string]Rate
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	stats   LimiterStats
+}
+
+func (l *limiter) middleware(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*Response, error) {
+		if err := l.wait(req); err != nil {
+			return nil, err
+		}
+		return next.Do(req)
+	})
+}
+
+// wait blocks until req may be sent or its context is done.
+func (l *limiter) wait(req *http.Request) error {
+	start := time.Now()
+	buckets := []*tokenBucket{l.bucket(req), l.global}
+
+	var wait time.Duration
+	var reserved []*tokenBucket
+	for _, b := range buckets {
+		if b == nil {
+			continue
+		}
+		wait = max(wait, b.reserve(start))
+		reserved = append(reserved, b)
+	}
+	if err := sleep(req.Context(), wait); err != nil {
+		for _, b := range reserved {
+			b.cancel()
+		}
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Requests++
+	if wait > 0 {
+		waited := time.Since(start)
+		l.stats.Delayed++
+		l.stats.TotalWait += waited
+		l.stats.MaxWait = max(l.stats.MaxWait, waited)
+	}
+	return nil
+}
+
+// bucket returns the token bucket of the host of req, nil if it is not limited.
+func (l *limiter) bucket(req *http.Request) *tokenBucket {
+	host := req.URL.Host
+	r, ok := l.rates[host]
+	if !ok {
+		host = req.URL.Hostname()
+		if r, ok = l.rates[host]; !ok {
+			return nil
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = newTokenBucket(r)
+		l.buckets[host] = b
+	}
+	return b
+}
+
+// tokenBucket is a token bucket refilled at a constant rate.
+type tokenBucket struct {
+	rate Rate
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(r Rate) *tokenBucket {
+	r.Burst = max(r.Burst, 1)
+	return &tokenBucket{rate: r, tokens: float64(r.Burst)}
+}
+
+// reserve takes a token and returns how long to wait before it is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.rate.PerSecond <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = min(b.tokens+elapsed*b.rate.PerSecond, float64(b.rate.Burst))
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate.PerSecond * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve.
+func (b *tokenBucket) cancel() {
+	if b.rate.PerSecond <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+1, float64(b.rate.Burst))
+}
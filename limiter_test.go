@@ -0,0 +1,86 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(Rate{PerSecond: 10, Burst: 2})
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, b.reserve(now))
+	b.cancel()
+	assert.Equal(t, 200*time.Millisecond, b.reserve(now))
+
+	// Tokens refill with time, up to the burst.
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(time.Hour)))
+	assert.Equal(t, time.Duration(0), b.reserve(now.Add(time.Hour)))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now.Add(time.Hour)))
+
+	unlimited := newTokenBucket(Rate{})
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), unlimited.reserve(now))
+	}
+}
+
+func TestSession_RateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	s := NewSession(WithRateLimit(Rate{PerSecond: 20, Burst: 1}))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := s.Get(ts.URL)
+		assert.Nil(t, err)
+		_ = resp.Close()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	stats := s.LimiterStats()
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Delayed)
+	assert.Greater(t, stats.TotalWait, time.Duration(0))
+	assert.GreaterOrEqual(t, stats.TotalWait, stats.MaxWait)
+}
+
+func TestSession_HostRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	s := NewSession(
+		WithHostRateLimit("other.example.com", Rate{PerSecond: 0.001, Burst: 1}),
+		WithHostRateLimit(u.Host, Rate{PerSecond: 0.001, Burst: 1}),
+	)
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := s.NewRequestWithContext(ctx, "GET", ts.URL)
+	_, err = s.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(1), s.LimiterStats().Requests)
+}
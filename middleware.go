@@ -0,0 +1,44 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import "net/http"
+
+// A Doer sends an HTTP request and returns the Response.
+type Doer interface {
+	Do(req *http.Request) (*Response, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(req *http.Request) (*Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*Response, error) {
+	return f(req)
+}
+
+// A Middleware wraps a Doer to observe or rewrite the request and the response.
+//
+// A middleware may modify the request before calling next, inspect or
+// replace the Response returned by next, or return its own Response
+// without calling next at all.
+type Middleware func(next Doer) Doer
+
+// chain wraps d with mws, the first middleware being the outermost one.
+func chain(d Doer, mws []Middleware) Doer {
+	for i := len(mws) - 1; i >= 0; i-- {
+		d = mws[i](d)
+	}
+	return d
+}
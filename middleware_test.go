@@ -0,0 +1,105 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func recordMiddleware(name string, calls *[]string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next.Do(req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		})
+	}
+}
+
+func TestSession_Middleware(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Added")
+		w.Header().Set("X-Server", "yes")
+	}))
+	defer ts.Close()
+
+	var calls []string
+	rewrite := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			req.Header.Set("X-Added", "mw")
+			resp, err := next.Do(req)
+			if err == nil {
+				resp.Header.Set("X-Seen", resp.Header.Get("X-Server"))
+			}
+			return resp, err
+		})
+	}
+	s := NewSession(WithMiddleware(recordMiddleware("a", &calls), recordMiddleware("b", &calls)))
+	s.Use(rewrite)
+
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, []string{"a before", "b before", "b after", "a after"}, calls)
+	assert.Equal(t, "mw", got)
+	assert.Equal(t, "yes", resp.Header.Get("X-Seen"))
+}
+
+func TestSession_MiddlewareShortCircuit(t *testing.T) {
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer ts.Close()
+
+	fake := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			return NewResponse(&http.Response{
+				StatusCode: http.StatusTeapot,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("fake")),
+				Request:    req,
+			}), nil
+		})
+	}
+	s := NewSession(WithMiddleware(fake))
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	text, _ := resp.Text()
+	assert.Equal(t, "fake", text)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	assert.False(t, called)
+}
+
+func TestDefaultSession_Middleware(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	old := DefaultSession.middlewares
+	defer func() { DefaultSession.middlewares = old }()
+
+	var calls []string
+	DefaultSession.Use(recordMiddleware("default", &calls))
+	resp, err := Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, []string{"default before", "default after"}, calls)
+}
@@ -0,0 +1,507 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// errBodyNotReplayable is returned when reopening a part read only once.
+var errBodyNotReplayable = errors.New("multipart: part cannot be read again")
+
+// StreamMultipartForm sets a multipart/form-data request body like
+// MultipartForm, with the fields sorted by name, but streams the parts
+// while sending instead of buffering them in memory.
+//
+// Files are sent as file parts, other readers as fields. The Content-Length
+// is set when the sizes of all the parts are known, that is for files,
+// *bytes.Buffer, *bytes.Reader and *strings.Reader. Files are reopened by
+// name to send the body again on retries and redirects, which is not
+// possible if a part is another reader.
+func StreamMultipartForm(form map[string]io.Reader) RequestOption {
+	return func(req *http.Request) error {
+		b := &multipartBody{}
+		for _, name := range slices.Sorted(maps.Keys(form)) {
+			var p *multipartPart
+			if f, ok := form[name].(*os.File); ok {
+				var err error
+				if p, err = filePart(name, f); err != nil {
+					return err
+				}
+			} else {
+				p = readerPart(form[name])
+				p.header = fieldHeader(name)
+			}
+			b.parts = append(b.parts, p)
+		}
+		return b.setBody(req)
+	}
+}
+
+// Multipart builds a multipart/form-data request body, made of ordered
+// parts streamed while sending, see StreamMultipartForm.
+//
+//	m := NewMultipart().
+//		Field("title", "report").
+//		File("files[]", "report.pdf").
+//		Bytes("files[]", "data.csv", data, PartContentType("text/csv"))
+//	resp, err := Post(url, MultipartBody(m))
+//
+// The Content-Type of files is guessed from the extension of their filename,
+// or else sniffed from their content, unless set with PartContentType.
+type Multipart struct {
+	body multipartBody
+	err  error
+}
+
+// PartOption sets the header of a multipart part.
+type PartOption func(h textproto.MIMEHeader)
+
+// PartContentType sets the Content-Type of the part.
+func PartContentType(contentType string) PartOption {
+	return func(h textproto.MIMEHeader) {
+		h.Set("Content-Type", contentType)
+	}
+}
+
+// PartHeader sets the header key of the part to value.
+func PartHeader(key, value string) PartOption {
+	return func(h textproto.MIMEHeader) {
+		h.Set(key, value)
+	}
+}
+
+// NewMultipart returns an empty Multipart.
+func NewMultipart() *Multipart {
+	return &Multipart{}
+}
+
+// Boundary sets the boundary of the body, random by default.
+func (m *Multipart) Boundary(boundary string) *Multipart {
+	m.body.boundary = boundary
+	return m
+}
+
+// Field adds the form field name with value.
+func (m *Multipart) Field(name, value string, opts ...PartOption) *Multipart {
+	p := readerPart(strings.NewReader(value))
+	p.header = fieldHeader(name)
+	return m.add(p, opts)
+}
+
+// File adds the form file name with the content of the file path,
+// named after its base name.
+func (m *Multipart) File(name, path string, opts ...PartOption) *Multipart {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return m.fail(err)
+	}
+	p := &multipartPart{size: -1, replayable: true}
+	if fi.Mode().IsRegular() {
+		p.size = fi.Size()
+	}
+	p.open = func() (io.ReadCloser, error) {
+		return &fileReader{name: path}, nil
+	}
+	return m.addFile(p, name, filepath.Base(path), opts)
+}
+
+// Reader adds the form file name named filename, with the content of r.
+func (m *Multipart) Reader(name, filename string, r io.Reader, opts ...PartOption) *Multipart {
+	return m.addFile(readerPart(r), name, filename, opts)
+}
+
+// Bytes adds the form file name named filename, with the content b.
+func (m *Multipart) Bytes(name, filename string, b []byte, opts ...PartOption) *Multipart {
+	return m.addFile(readerPart(bytes.NewReader(b)), name, filename, opts)
+}
+
+func (m *Multipart) addFile(p *multipartPart, name, filename string, opts []PartOption) *Multipart {
+	p.header = fileHeader(name, filename, "")
+	m.add(p, opts)
+	if m.err == nil && p.header.Get("Content-Type") == "" {
+		ct := mime.TypeByExtension(filepath.Ext(filename))
+		if ct == "" {
+			var err error
+			if ct, err = p.sniff(); err != nil {
+				return m.fail(err)
+			}
+		}
+		p.header.Set("Content-Type", ct)
+	}
+	return m
+}
+
+func (m *Multipart) add(p *multipartPart, opts []PartOption) *Multipart {
+	for _, opt := range opts {
+		opt(p.header)
+	}
+	m.body.parts = append(m.body.parts, p)
+	return m
+}
+
+// fail records the first error of the builder.
+func (m *Multipart) fail(err error) *Multipart {
+	if m.err == nil {
+		m.err = err
+	}
+	return m
+}
+
+// MultipartBody sets the multipart/form-data request body built by m.
+func MultipartBody(m *Multipart) RequestOption {
+	return func(req *http.Request) error {
+		if m.err != nil {
+			return m.err
+		}
+		return m.body.setBody(req)
+	}
+}
+
+// multipartPart is a part of a streamed multipart body.
+type multipartPart struct {
+	header textproto.MIMEHeader
+
+	// open returns the content of the part, for each sending of the body.
+	open func() (io.ReadCloser, error)
+
+	// size is the size of the content, -1 if unknown.
+	size int64
+
+	// replayable reports whether open can be called more than once.
+	replayable bool
+
+	// release closes the content given by the caller if it was never
+	// opened, nil if there is none.
+	release func()
+}
+
+// openOnce makes p open rc once, and close it on release if never opened.
+func (p *multipartPart) openOnce(rc io.ReadCloser) {
+	var opened atomic.Bool
+	p.open = func() (io.ReadCloser, error) {
+		if opened.Swap(true) {
+			return nil, errBodyNotReplayable
+		}
+		return rc, nil
+	}
+	p.release = func() {
+		if !opened.Swap(true) {
+			_ = rc.Close()
+		}
+	}
+}
+
+// sniff detects the Content-Type of the content of p, keeping the content
+// of parts read only once for sending.
+func (p *multipartPart) sniff() (string, error) {
+	r, err := p.open()
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		_ = r.Close()
+		return "", err
+	}
+	buf = buf[:n]
+	if p.replayable {
+		_ = r.Close()
+	} else {
+		p.openOnce(struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), r), r})
+	}
+	return http.DetectContentType(buf), nil
+}
+
+// multipartBody is a multipart body written to a pipe while sending.
+type multipartBody struct {
+	boundary string
+	parts    []*multipartPart
+}
+
+// setBody sets the body of req to b.
+func (b *multipartBody) setBody(req *http.Request) error {
+	if b.boundary == "" {
+		b.boundary = multipart.NewWriter(nil).Boundary()
+	}
+	size, replayable, err := b.contentLength()
+	if err != nil {
+		return err
+	}
+	req.Body = b.reader()
+	clearForm(req)
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+b.boundary)
+	req.ContentLength = max(size, 0)
+	req.GetBody = nil
+	if replayable {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return b.reader(), nil
+		}
+	}
+	return nil
+}
+
+// contentLength returns the size of the body, -1 if unknown, and whether it
+// can be sent again.
+func (b *multipartBody) contentLength() (int64, bool, error) {
+	// Write the headers and boundaries only.
+	var c countWriter
+	w := multipart.NewWriter(&c)
+	if err := w.SetBoundary(b.boundary); err != nil {
+		return 0, false, err
+	}
+	size, replayable := int64(0), true
+	for _, p := range b.parts {
+		if _, err := w.CreatePart(p.header); err != nil {
+			return 0, false, err
+		}
+		if p.size < 0 || size < 0 {
+			size = -1
+		} else {
+			size += p.size
+		}
+		replayable = replayable && p.replayable
+	}
+	if err := w.Close(); err != nil {
+		return 0, false, err
+	}
+	if size < 0 {
+		return -1, replayable, nil
+	}
+	return size + c.n, replayable, nil
+}
+
+// reader returns a reader of the body, reading the parts lazily.
+func (b *multipartBody) reader() io.ReadCloser {
+	return &multipartReader{b: b}
+}
+
+// release closes the contents of the parts given by the caller which
+// were never opened.
+func (b *multipartBody) release() {
+	for _, p := range b.parts {
+		if p.release != nil {
+			p.release()
+		}
+	}
+}
+
+// multipartReader reads a multipart body from a pipe, written by a
+// goroutine started on the first read. Closing it before reading
+// releases the parts.
+type multipartReader struct {
+	b *multipartBody
+
+	mu     sync.Mutex
+	pr     *io.PipeReader
+	closed bool
+}
+
+func (r *multipartReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	if r.pr == nil && !r.closed {
+		pr, pw := io.Pipe()
+		r.pr = pr
+		go func() {
+			pw.CloseWithError(r.b.writeTo(pw))
+			r.b.release()
+		}()
+	}
+	pr := r.pr
+	r.mu.Unlock()
+	if pr == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return pr.Read(p)
+}
+
+func (r *multipartReader) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	pr := r.pr
+	r.mu.Unlock()
+	if pr == nil {
+		r.b.release()
+		return nil
+	}
+	return pr.Close()
+}
+
+func (b *multipartBody) writeTo(dst io.Writer) error {
+	w := multipart.NewWriter(dst)
+	if err := w.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+	for _, p := range b.parts {
+		pw, err := w.CreatePart(p.header)
+		if err != nil {
+			return err
+		}
+		r, err := p.open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(pw, r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// fieldHeader returns the header of the form field name.
+func fieldHeader(name string) textproto.MIMEHeader {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="`+escapeQuotes(name)+`"`)
+	return h
+}
+
+// fileHeader returns the header of the form file name, without
+// Content-Type if contentType is empty.
+func fileHeader(name, filename, contentType string) textproto.MIMEHeader {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="`+escapeQuotes(name)+`"; filename="`+escapeQuotes(filename)+`"`)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	return h
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// filePart returns the file part name of f, sent from the beginning. The
+// file is reopened by name when sent again.
+func filePart(name string, f *os.File) (*multipartPart, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	p := &multipartPart{
+		header:     fileHeader(name, filepath.Base(f.Name()), "application/octet-stream"),
+		size:       -1,
+		replayable: true,
+	}
+	if fi.Mode().IsRegular() {
+		p.size = fi.Size()
+	}
+	var opened atomic.Bool
+	p.open = func() (io.ReadCloser, error) {
+		if !opened.Swap(true) {
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				_ = f.Close()
+				return nil, err
+			}
+			return f, nil
+		}
+		return &fileReader{name: f.Name()}, nil
+	}
+	p.release = func() {
+		if !opened.Swap(true) {
+			_ = f.Close()
+		}
+	}
+	return p, nil
+}
+
+// readerPart returns a part reading r. The part is replayable if r is a
+// *bytes.Buffer, *bytes.Reader or *strings.Reader.
+func readerPart(r io.Reader) *multipartPart {
+	p := &multipartPart{size: -1, replayable: true}
+	switch v := r.(type) {
+	case *bytes.Buffer:
+		buf := v.Bytes()
+		p.size = int64(len(buf))
+		p.open = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf)), nil
+		}
+	case *bytes.Reader:
+		p.size = int64(v.Len())
+		snapshot := *v
+		p.open = func() (io.ReadCloser, error) {
+			r := snapshot
+			return io.NopCloser(&r), nil
+		}
+	case *strings.Reader:
+		p.size = int64(v.Len())
+		snapshot := *v
+		p.open = func() (io.ReadCloser, error) {
+			r := snapshot
+			return io.NopCloser(&r), nil
+		}
+	default:
+		p.replayable = false
+		rc, ok := r.(io.ReadCloser)
+		if !ok {
+			rc = io.NopCloser(r)
+		}
+		p.openOnce(rc)
+	}
+	return p
+}
+
+// fileReader reads the file name, opened on the first read.
+type fileReader struct {
+	name string
+	f    *os.File
+	err  error
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	if r.f == nil && r.err == nil {
+		r.f, r.err = os.Open(r.name)
+	}
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.f.Read(p)
+}
+
+func (r *fileReader) Close() error {
+	if r.f == nil {
+		return nil
+	}
+	return r.f.Close()
+}
+
+// countWriter counts the bytes written to it.
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
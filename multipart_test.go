@@ -0,0 +1,240 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// multipartServer echoes the fields and files of multipart forms.
+func multipartServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m := M{}
+		for k, v := range r.MultipartForm.Value {
+			m[k] = v[0]
+		}
+		for k, v := range r.MultipartForm.File {
+			f, _ := v[0].Open()
+			b, _ := io.ReadAll(f)
+			m[k] = v[0].Filename + ":" + v[0].Header.Get("Content-Type") + ":" + string(b)
+		}
+		w.Header().Set("Content-Type", AppJSON)
+		json.NewEncoder(w).Encode(m)
+	}))
+}
+
+func TestStreamMultipartForm(t *testing.T) {
+	f, err := os.Open("testdata/file4upload")
+	assert.Nil(t, err)
+	content, _ := os.ReadFile("testdata/file4upload")
+	form := StreamMultipartForm(map[string]io.Reader{
+		"field1": strings.NewReader("value1"),
+		"field2": bytes.NewBufferString("value2"),
+		"file1":  f,
+	})
+
+	req, err := NewRequest("POST", "http://example.com", form)
+	assert.Nil(t, err)
+	assert.Contains(t, req.Header.Get("Content-Type"), "multipart/form-data; boundary=")
+
+	b, err := io.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(b)), req.ContentLength)
+	assert.Less(t, strings.Index(string(b), "field1"), strings.Index(string(b), "file1"))
+
+	// The file is reopened.
+	body, err := req.GetBody()
+	assert.Nil(t, err)
+	b2, err := io.ReadAll(body)
+	assert.Nil(t, err)
+	assert.Equal(t, b, b2)
+	assert.Contains(t, string(b2), string(content))
+	_ = body.Close()
+}
+
+func TestStreamMultipartForm_Unknown(t *testing.T) {
+	form := StreamMultipartForm(map[string]io.Reader{
+		"field": iotest.HalfReader(strings.NewReader("value")),
+	})
+	req, err := NewRequest("POST", "http://example.com", form)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), req.ContentLength)
+	assert.Nil(t, req.GetBody)
+	b, err := io.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "value")
+}
+
+func TestStreamMultipartForm_Send(t *testing.T) {
+	ts := multipartServer()
+	defer ts.Close()
+
+	f, err := os.Open("testdata/file4upload")
+	assert.Nil(t, err)
+	content, _ := os.ReadFile("testdata/file4upload")
+	resp, err := Post(ts.URL, StreamMultipartForm(map[string]io.Reader{
+		"field": strings.NewReader("value"),
+		"file":  f,
+	}))
+	assert.Nil(t, err)
+	var m map[string]string
+	assert.Nil(t, resp.JSON(&m))
+	assert.Equal(t, map[string]string{
+		"field": "value",
+		"file":  "file4upload:application/octet-stream:" + string(content),
+	}, m)
+
+	// Sent again by the retries.
+	attempts := 0
+	ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(b)
+	}))
+	defer ts2.Close()
+	f, err = os.Open("testdata/file4upload")
+	assert.Nil(t, err)
+	s := NewSession(WithRetry(fastRetry))
+	resp, err = s.Post(ts2.URL, StreamMultipartForm(map[string]io.Reader{"file": f}),
+		Headers(M{"Idempotency-Key": "1"}))
+	assert.Nil(t, err)
+	txt, _ := resp.Text()
+	assert.Contains(t, txt, string(content))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestMultipart(t *testing.T) {
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A")
+	m := NewMultipart().
+		Boundary("test-boundary").
+		Field("title", "report").
+		File("files[]", "testdata/file4upload").
+		Bytes("files[]", "image", png).
+		Reader("files[]", "data.csv", iotest.HalfReader(strings.NewReader("a,b")), PartHeader("X-Part", "3"))
+
+	req, err := NewRequest("POST", "http://example.com", MultipartBody(m))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/form-data; boundary=test-boundary", req.Header.Get("Content-Type"))
+	assert.Nil(t, req.GetBody)
+	b, err := io.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "--test-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"title\"\r\n\r\n"+
+		"report\r\n--test-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"files[]\"; filename=\"file4upload\"\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n\r\n"+
+		"Hey, I am test file.\r\n--test-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"files[]\"; filename=\"image\"\r\n"+
+		"Content-Type: image/png\r\n\r\n"+
+		string(png)+"\r\n--test-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"files[]\"; filename=\"data.csv\"\r\n"+
+		"Content-Type: text/csv; charset=utf-8\r\n"+
+		"X-Part: 3\r\n\r\n"+
+		"a,b\r\n--test-boundary--\r\n", string(b))
+}
+
+func TestMultipart_Send(t *testing.T) {
+	ts := multipartServer()
+	defer ts.Close()
+
+	m := NewMultipart().
+		Field("field", "value").
+		Bytes("file", "notes", []byte("some notes"), PartContentType("text/markdown"))
+	resp, err := Post(ts.URL, MultipartBody(m))
+	assert.Nil(t, err)
+	var got map[string]string
+	assert.Nil(t, resp.JSON(&got))
+	assert.Equal(t, map[string]string{
+		"field": "value",
+		"file":  "notes:text/markdown:some notes",
+	}, got)
+
+	req, err := NewRequest("POST", ts.URL, MultipartBody(m))
+	assert.Nil(t, err)
+	b, _ := io.ReadAll(req.Body)
+	assert.Equal(t, int64(len(b)), req.ContentLength)
+
+	_, err = NewRequest("POST", ts.URL, MultipartBody(NewMultipart().File("file", "testdata/missing")))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = NewRequest("POST", ts.URL, MultipartBody(NewMultipart().Boundary("bad boundary ")))
+	assert.NotNil(t, err)
+}
+
+func TestStreamMultipartForm_Unsent(t *testing.T) {
+	ts := multipartServer()
+	defer ts.Close()
+	s := NewSession(WithCircuitBreaker(BreakerPolicy{ConsecutiveFailures: 1, OpenTimeout: time.Hour}))
+	s.breaker.circuit(mustParseURL(ts.URL).Host).record(true, time.Now())
+
+	before := runtime.NumGoroutine()
+	var files []*os.File
+	for range 20 {
+		f, err := os.Open("testdata/file4upload")
+		assert.Nil(t, err)
+		files = append(files, f)
+		form := StreamMultipartForm(map[string]io.Reader{"file": f})
+
+		// Replaced by a later body, and dropped.
+		_, err = NewRequest("POST", ts.URL, form, Body(strings.NewReader("body")))
+		assert.Nil(t, err)
+		// Not sent while the circuit is open.
+		_, err = s.Post(ts.URL, StreamMultipartForm(map[string]io.Reader{"field": strings.NewReader("v")}))
+		assert.True(t, errors.Is(err, ErrCircuitOpen))
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	// Closing the body before reading it closes the file.
+	f := files[0]
+	req, err := NewRequest("POST", ts.URL, StreamMultipartForm(map[string]io.Reader{"file": f}))
+	assert.Nil(t, err)
+	assert.Nil(t, req.Body.Close())
+	_, err = f.Stat()
+	assert.True(t, errors.Is(err, os.ErrClosed))
+	_, err = req.Body.Read(make([]byte, 1))
+	assert.Equal(t, io.ErrClosedPipe, err)
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
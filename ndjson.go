@@ -0,0 +1,76 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// NDJSON iterates over the lines of a newline delimited JSON body,
+// such as application/x-ndjson or JSON Lines, decoding each one into T.
+//
+// Lines are read one at a time whatever their length, blank lines are
+// skipped. Decoding errors report the line number. The iteration stops
+// after yielding an error, or the context error once the context of the
+// request is done. Unless the body was already cached by Content,
+// it is consumed and cannot be read again.
+func NDJSON[T any](r *Response) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		body, err := r.stream()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		ctx := context.Background()
+		if r.Request != nil {
+			ctx = r.Request.Context()
+		}
+
+		br := bufio.NewReader(body)
+		for n := 1; ; n++ {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			line, err := br.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				}
+				yield(zero, fmt.Errorf("ndjson line %d: %w", n, err))
+				return
+			}
+			if line = bytes.TrimSpace(line); len(line) != 0 {
+				var v T
+				if err := json.Unmarshal(line, &v); err != nil {
+					yield(zero, fmt.Errorf("ndjson line %d: %w", n, err))
+					return
+				}
+				if !yield(v, nil) {
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}
+}
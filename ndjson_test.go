@@ -0,0 +1,86 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNDJSON(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	body := `{"name": "a", "age": 1}` + "\r\n" +
+		"\n" +
+		`{"name": "` + long + `", "age": 2}` + "\n" +
+		`{"name": "c", "age": 3}`
+
+	var items []respData
+	for v, err := range NDJSON[respData](jsonResponse(body)) {
+		assert.Nil(t, err)
+		items = append(items, v)
+	}
+	assert.Equal(t, []respData{{"a", 1}, {long, 2}, {"c", 3}}, items)
+
+	var lastErr error
+	count := 0
+	for _, err := range NDJSON[respData](jsonResponse("{\"age\": 1}\n{\"age\": 2}\n{oops}\n{\"age\": 4}\n")) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	assert.Equal(t, 2, count)
+	assert.ErrorContains(t, lastErr, "ndjson line 3")
+}
+
+func TestNDJSON_ContextCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for {
+			if _, err := w.Write([]byte("{\"age\": 1}\n")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := NewRequestWithContext(ctx, "GET", ts.URL)
+	resp, err := Do(req)
+	assert.Nil(t, err)
+	defer resp.Close()
+
+	count := 0
+	var lastErr error
+	for _, err := range NDJSON[respData](resp) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if count++; count == 3 {
+			cancel()
+		}
+	}
+	assert.Equal(t, 3, count)
+	assert.ErrorIs(t, lastErr, context.Canceled)
+}
@@ -0,0 +1,111 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const problemBody = `{
+	"type": "https://example.com/probs/out-of-credit",
+	"title": "You do not have enough credit.",
+	"status": 403,
+	"detail": "Your current balance is 30, but that costs 50.",
+	"instance": "/account/12345/msgs/abc",
+	"balance": 30
+}`
+
+func TestProblemDetails_JSON(t *testing.T) {
+	var p ProblemDetails
+	assert.Nil(t, json.Unmarshal([]byte(problemBody), &p))
+	assert.Equal(t, ProblemDetails{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "You do not have enough credit.",
+		Status:     403,
+		Detail:     "Your current balance is 30, but that costs 50.",
+		Instance:   "/account/12345/msgs/abc",
+		Extensions: map[string]any{"balance": float64(30)},
+	}, p)
+	assert.Equal(t, "You do not have enough credit.: Your current balance is 30, but that costs 50.", p.Error())
+
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.JSONEq(t, problemBody, string(b))
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string { return e.Code + ": " + e.Message }
+
+func errorServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(problemBody))
+		case "/envelope":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": "invalid", "message": "name is required"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("oops"))
+		}
+	}))
+}
+
+func TestSession_ErrorBody(t *testing.T) {
+	ts := errorServer()
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL), WithRaiseForStatus())
+	_, err := s.Get("problem")
+	var problem *ProblemDetails
+	assert.True(t, errors.As(err, &problem))
+	assert.Equal(t, 403, problem.Status)
+	assert.Equal(t, float64(30), problem.Extensions["balance"])
+
+	_, err = s.Get("envelope")
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Nil(t, httpErr.Decoded)
+
+	s = NewSession(WithBaseURL(ts.URL), WithRaiseForStatus(), WithErrorBody[apiError]())
+	_, err = s.Get("envelope")
+	var apiErr *apiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, &apiError{Code: "invalid", Message: "name is required"}, apiErr)
+
+	_, err = s.Get("text")
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Nil(t, httpErr.Decoded)
+	assert.Equal(t, "oops", string(httpErr.Body))
+
+	// The registered type is used without WithRaiseForStatus too.
+	s = NewSession(WithBaseURL(ts.URL), WithErrorBody[apiError]())
+	resp, err := s.Get("envelope")
+	assert.Nil(t, err)
+	err = resp.RaiseForStatus()
+	assert.True(t, errors.As(err, &apiErr))
+	_ = resp.Close()
+}
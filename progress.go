@@ -0,0 +1,163 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TransferProgress is the state of a transfer.
+type TransferProgress struct {
+	// Done is the number of bytes transferred.
+	Done int64
+
+	// Total is the number of bytes to transfer, -1 if unknown.
+	Total int64
+
+	// Rate is the average number of bytes transferred per second.
+	Rate float64
+}
+
+// ProgressFunc is called with the progress of a transfer.
+type ProgressFunc func(p TransferProgress)
+
+// Progress calls fn with the progress of the upload of the request body,
+// at most once per interval and once at the end. For Session.Download, it
+// reports the progress of the download instead, over all the resumed or
+// parallel requests.
+//
+// See Response.Progress for the download of a response body.
+func Progress(interval time.Duration, fn ProgressFunc) RequestOption {
+	return func(req *http.Request) error {
+		setTransfer(req, func(c *transferConfig) {
+			c.progressInterval = interval
+			c.progress = fn
+		})
+		return nil
+	}
+}
+
+// Progress makes the reads of the body call fn with their progress, at most
+// once per interval and once at the end. It must be called before reading
+// the body, by Content or SaveContent for instance.
+func (r *Response) Progress(interval time.Duration, fn ProgressFunc) *Response {
+	if r.Body != nil {
+		r.Body = &progressReader{r.Body, newProgress(fn, interval, r.ContentLength)}
+	}
+	return r
+}
+
+// progress reports the progress of a transfer, counting the bytes written
+// to it. A nil *progress discards them.
+type progress struct {
+	fn       ProgressFunc
+	interval time.Duration
+
+	mu       sync.Mutex
+	start    time.Time
+	last     time.Time
+	done     int64
+	total    int64
+	finished bool
+}
+
+func newProgress(fn ProgressFunc, interval time.Duration, total int64) *progress {
+	if fn == nil {
+		return nil
+	}
+	if total < 0 {
+		total = -1
+	}
+	now := time.Now()
+	return &progress{fn: fn, interval: interval, start: now, last: now, total: total}
+}
+
+// reset sets the bytes already transferred and the total.
+func (p *progress) reset(done, total int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if total < 0 {
+		total = -1
+	}
+	p.done, p.total = done, total
+	p.finished = false
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	if p == nil {
+		return len(b), nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += int64(len(b))
+	now := time.Now()
+	switch {
+	case p.finished:
+	case p.done == p.total:
+		p.finished = true
+		p.report(now)
+	case now.Sub(p.last) >= p.interval:
+		p.last = now
+		p.report(now)
+	}
+	return len(b), nil
+}
+
+// finish reports the end of the transfer, unless it was already reported.
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.finished {
+		p.finished = true
+		p.report(time.Now())
+	}
+}
+
+// report calls fn, with p locked.
+func (p *progress) report(now time.Time) {
+	var rate float64
+	if d := now.Sub(p.start).Seconds(); d > 0 {
+		rate = float64(p.done) / d
+	}
+	p.fn(TransferProgress{Done: p.done, Total: p.total, Rate: rate})
+}
+
+// progressReader reports the progress of the reads of a body.
+type progressReader struct {
+	io.ReadCloser
+	p *progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.p.Write(b[:n])
+	if err == io.EOF {
+		r.p.finish()
+	}
+	return n, err
+}
+
+func (r *progressReader) Close() error {
+	r.p.finish()
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,141 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// progressRecorder records the reported progress.
+type progressRecorder struct {
+	mu      sync.Mutex
+	reports []TransferProgress
+}
+
+func (r *progressRecorder) record(p TransferProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, p)
+}
+
+func (r *progressRecorder) last() TransferProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.reports) == 0 {
+		return TransferProgress{}
+	}
+	return r.reports[len(r.reports)-1]
+}
+
+func TestProgress(t *testing.T) {
+	rec := &progressRecorder{}
+	p := newProgress(rec.record, 0, 6)
+	p.Write([]byte("abc"))
+	p.Write([]byte("def"))
+	p.finish()
+	assert.Equal(t, 2, len(rec.reports))
+	assert.Equal(t, int64(3), rec.reports[0].Done)
+	assert.Equal(t, int64(6), rec.last().Done)
+	assert.Equal(t, int64(6), rec.last().Total)
+	assert.True(t, rec.last().Rate > 0)
+
+	// Throttled, with an unknown total.
+	rec = &progressRecorder{}
+	p = newProgress(rec.record, time.Hour, -1)
+	p.Write([]byte("abc"))
+	p.Write([]byte("def"))
+	p.finish()
+	p.finish()
+	assert.Equal(t, []int64{6, -1}, []int64{rec.last().Done, rec.last().Total})
+	assert.Equal(t, 1, len(rec.reports))
+
+	// A nil progress discards the writes.
+	p = newProgress(nil, 0, 0)
+	n, err := p.Write([]byte("abc"))
+	assert.Equal(t, 3, n)
+	assert.Nil(t, err)
+	p.finish()
+}
+
+func TestProgress_Upload(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer ts.Close()
+	content := strings.Repeat("x", 1<<20)
+
+	rec := &progressRecorder{}
+	_, err := Post(ts.URL, Progress(0, rec.record), Body(strings.NewReader(content)))
+	assert.Nil(t, err)
+	assert.Equal(t, TransferProgress{Done: 1 << 20, Total: 1 << 20}, TransferProgress{Done: rec.last().Done, Total: rec.last().Total})
+
+	// Unknown Content-Length.
+	rec = &progressRecorder{}
+	_, err = Post(ts.URL, Body(io.MultiReader(strings.NewReader(content))), Progress(time.Hour, rec.record))
+	assert.Nil(t, err)
+	assert.Equal(t, TransferProgress{Done: 1 << 20, Total: -1}, TransferProgress{Done: rec.last().Done, Total: rec.last().Total})
+}
+
+func TestResponse_Progress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chunked" {
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+		}
+		w.Write(downloadContent)
+	}))
+	defer ts.Close()
+	size := int64(len(downloadContent))
+
+	rec := &progressRecorder{}
+	resp, _ := Get(ts.URL)
+	assert.Nil(t, resp.Progress(0, rec.record).SaveContent(filepath.Join(t.TempDir(), "file")))
+	assert.Equal(t, []int64{size, size}, []int64{rec.last().Done, rec.last().Total})
+
+	rec = &progressRecorder{}
+	resp, _ = Get(ts.URL + "/chunked")
+	content, _ := resp.Progress(0, rec.record).Content()
+	assert.True(t, bytes.Equal(downloadContent, content))
+	assert.Equal(t, []int64{size, -1}, []int64{rec.last().Done, rec.last().Total})
+}
+
+func TestProgress_Download(t *testing.T) {
+	size := int64(len(downloadContent))
+
+	ds := newDownloadServer(1)
+	defer ds.Close()
+	rec := &progressRecorder{}
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ds.URL, path, Progress(0, rec.record)))
+	assert.Equal(t, []int64{size, size}, []int64{rec.last().Done, rec.last().Total})
+	for _, p := range rec.reports {
+		assert.True(t, p.Done <= size)
+	}
+
+	ss := newSegmentServer("bytes=0-349525")
+	defer ss.Close()
+	rec = &progressRecorder{}
+	path = filepath.Join(t.TempDir(), "segments")
+	assert.Nil(t, NewSession().Download(ss.URL, path, Segments(3), Progress(time.Millisecond, rec.record)))
+	assert.Equal(t, []int64{size, size}, []int64{rec.last().Done, rec.last().Total})
+}
@@ -0,0 +1,130 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unix timestamps are told apart from delays in seconds
+// in X-RateLimit-Reset headers by this threshold.
+const epochThreshold = 1_000_000_000
+
+// RateLimit is the rate limit state reported by the server
+// in X-RateLimit-* or RateLimit-* headers.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the window, -1 if unknown.
+	Limit int
+
+	// Remaining is the number of requests left in the window, -1 if unknown.
+	Remaining int
+
+	// Reset is the time when the window resets, zero if unknown.
+	Reset time.Time
+}
+
+// RateLimit parses the rate limit headers of the response.
+// It returns false if the response has none.
+//
+// Both the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset
+// headers and the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers of the IETF draft are supported, as well as the combined RateLimit
+// header. A reset value is read as a Unix timestamp if it is large enough,
+// and as a delay in seconds otherwise.
+func (r *Response) RateLimit() (RateLimit, bool) {
+	return parseRateLimit(r.Header, time.Now())
+}
+
+// RetryAfter parses the Retry-After header of the response,
+// given either in seconds or as an HTTP date.
+// It returns false if the header is absent or invalid.
+func (r *Response) RetryAfter() (time.Duration, bool) {
+	return parseRetryAfter(r.Header.Get("Retry-After"), time.Now())
+}
+
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
+
+func parseRateLimit(h http.Header, now time.Time) (RateLimit, bool) {
+	rl := RateLimit{Limit: -1, Remaining: -1}
+	found := false
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if n, ok := leadingInt(h.Get(prefix + "Limit")); ok {
+			rl.Limit, found = n, true
+		}
+		if n, ok := leadingInt(h.Get(prefix + "Remaining")); ok {
+			rl.Remaining, found = n, true
+		}
+		if n, ok := leadingInt(h.Get(prefix + "Reset")); ok {
+			rl.Reset, found = resetTime(n, now), true
+		}
+	}
+
+	// Combined header, e.g. "limit=100, remaining=50, reset=30"
+	// or "default;r=50;t=30".
+	if v := h.Get("RateLimit"); v != "" {
+		for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+			key, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				continue
+			}
+			switch strings.ToLower(key) {
+			case "limit", "l":
+				rl.Limit, found = n, true
+			case "remaining", "r":
+				rl.Remaining, found = n, true
+			case "reset", "t":
+				rl.Reset, found = resetTime(n, now), true
+			}
+		}
+	}
+	return rl, found
+}
+
+// leadingInt parses the integer at the beginning of v, such as 100 in "100, 100;w=60".
+func leadingInt(v string) (int, bool) {
+	v = strings.TrimSpace(v)
+	end := strings.IndexAny(v, ",; ")
+	if end >= 0 {
+		v = v[:end]
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+func resetTime(n int, now time.Time) time.Time {
+	if n >= epochThreshold {
+		return time.Unix(int64(n), 0)
+	}
+	return now.Add(time.Duration(n) * time.Second)
+}
@@ -0,0 +1,91 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 2, 2, 10, 0, 0, 0, time.UTC)
+	var testcases = []struct {
+		v    string
+		want time.Duration
+		ok   bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"-3", 0, true},
+		{"Tue, 02 Feb 2021 10:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 02 Feb 2021 09:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tc := range testcases {
+		d, ok := parseRetryAfter(tc.v, now)
+		assert.Equal(t, tc.ok, ok, tc.v)
+		assert.Equal(t, tc.want, d, tc.v)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2021, 2, 2, 10, 0, 0, 0, time.UTC)
+	var testcases = map[string]struct {
+		header http.Header
+		want   RateLimit
+		ok     bool
+	}{
+		"none": {
+			http.Header{},
+			RateLimit{Limit: -1, Remaining: -1},
+			false,
+		},
+		"x-ratelimit with timestamp": {
+			http.Header{
+				"X-Ratelimit-Limit":     {"5000"},
+				"X-Ratelimit-Remaining": {"4999"},
+				"X-Ratelimit-Reset":     {"1612260000"},
+			},
+			RateLimit{Limit: 5000, Remaining: 4999, Reset: time.Unix(1612260000, 0)},
+			true,
+		},
+		"ratelimit draft": {
+			http.Header{
+				"Ratelimit-Limit":     {"100, 100;w=60"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"30"},
+			},
+			RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(30 * time.Second)},
+			true,
+		},
+		"combined": {
+			http.Header{"Ratelimit": {"limit=10, remaining=3, reset=5"}},
+			RateLimit{Limit: 10, Remaining: 3, Reset: now.Add(5 * time.Second)},
+			true,
+		},
+		"combined structured": {
+			http.Header{"Ratelimit": {`"default";r=7;t=2`}},
+			RateLimit{Limit: -1, Remaining: 7, Reset: now.Add(2 * time.Second)},
+			true,
+		},
+	}
+	for name, tc := range testcases {
+		rl, ok := parseRateLimit(tc.header, now)
+		assert.Equal(t, tc.ok, ok, name)
+		assert.Equal(t, tc.want, rl, name)
+	}
+}
@@ -30,6 +30,7 @@ func NewRequest(method, url string, opts ...RequestOption) (*http.Request, error
 // only the last one will take effect.
 // The order of options all will effect final request status.
 func NewRequestWithContext(ctx context.Context, method, url string, opts ...RequestOption) (*http.Request, error) {
+	ctx, url = withTemplate(ctx, url)
 	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, err
@@ -39,77 +40,48 @@ func NewRequestWithContext(ctx context.Context, method, url string, opts ...Requ
 			return nil, err
 		}
 	}
+	if err := expandTemplate(req); err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
 // Get sends "GET" request.
 func Get(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("GET", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("GET", url, opts...)
 }
 
 // Post sends "POST" request.
 func Post(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("POST", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("POST", url, opts...)
 }
 
 // Put sends "Put" request.
 func Put(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("PUT", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("PUT", url, opts...)
 }
 
 // Patch sends "PATCH" request.
 func Patch(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("PATCH", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("PATCH", url, opts...)
 }
 
 // Head sends "HEAD" request.
 func Head(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("HEAD", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("HEAD", url, opts...)
 }
 
 // Options sends "OPTIONS" request.
 func Options(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("OPTIONS", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("OPTIONS", url, opts...)
 }
 
 // Delete sends "DELETE" request.
 func Delete(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("DELETE", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return Do(req)
+	return DefaultSession.request("DELETE", url, opts...)
 }
 
-// Do sends request and return the response.
+// Do sends request with DefaultSession and return the response.
 func Do(req *http.Request) (*Response, error) {
-	resp, err := (&http.Client{}).Do(req)
-	if err != nil {
-		return nil, err
-	}
-	return NewResponse(resp), nil
+	return DefaultSession.Do(req)
 }
@@ -75,25 +75,35 @@ func JSON(v interface{}) RequestOption {
 	}
 }
 
-// FileContent loads file content, and set it be request body.
+// FileContent sets the file content as request body.
+//
+// The file is streamed while sending, and reopened to send the body again
+// on retries and redirects.
 func FileContent(filename string) RequestOption {
 	return func(req *http.Request) error {
-		f, err := os.Open(filename)
+		fi, err := os.Stat(filename)
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-
-		// Copy file to new ReaderCloser, not use file directly
-		b := &bytes.Buffer{}
-		if _, err := io.Copy(b, f); err != nil {
-			return err
+		req.Body = &fileReader{name: filename}
+		clearForm(req)
+		req.GetBody = func() (io.ReadCloser, error) {
+			return &fileReader{name: filename}, nil
+		}
+		req.ContentLength = 0
+		if fi.Mode().IsRegular() {
+			req.ContentLength = fi.Size()
+			if fi.Size() == 0 {
+				req.Body = http.NoBody
+				req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+			}
 		}
-		return setRequestBody(req, b)
+		return nil
 	}
 }
 
 // MultipartForm sets a multipart/form-data request body.
+// The parts are buffered in memory, see StreamMultipartForm to stream them.
 func MultipartForm(form map[string]io.Reader) RequestOption {
 	return func(req *http.Request) error {
 		var b bytes.Buffer
@@ -129,8 +139,16 @@ func MultipartForm(form map[string]io.Reader) RequestOption {
 }
 
 // Cookies add the cookie to http.Request.
+// It replaces any existing cookies with the same name.
 func Cookies(c map[string]string) RequestOption {
 	return func(req *http.Request) error {
+		existing := req.Cookies()
+		req.Header.Del("Cookie")
+		for _, cookie := range existing {
+			if _, ok := c[cookie.Name]; !ok {
+				req.AddCookie(cookie)
+			}
+		}
 		for k, v := range c {
 			req.AddCookie(&http.Cookie{Name: k, Value: v})
 		}
@@ -139,16 +157,20 @@ func Cookies(c map[string]string) RequestOption {
 }
 
 // Data sends form-encoded data.
+// It merges with the data of a previous Data option, replacing the values
+// of existing keys, unless another option set the body meanwhile.
 func Data(form map[string]string) RequestOption {
 	return func(req *http.Request) error {
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		if len(req.PostForm) == 0 {
-			req.PostForm = make(url.Values)
+		values := make(url.Values)
+		if req.PostForm != nil && mediaType(req.Header.Get("Content-Type")) == AppFormURLEncoded {
+			for k, vs := range req.PostForm {
+				values[k] = vs
+			}
 		}
 		for k, v := range form {
-			req.PostForm.Set(k, v)
+			values.Set(k, v)
 		}
-		return nil
+		return setFormBody(req, values)
 	}
 }
 
@@ -159,12 +181,35 @@ func Body(body io.Reader) RequestOption {
 	}
 }
 
+// setFormBody sets the form-encoded request body values,
+// which are kept in PostForm.
+func setFormBody(req *http.Request, values url.Values) error {
+	if err := setRequestBody(req, strings.NewReader(values.Encode())); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", AppFormURLEncoded)
+	req.PostForm = values
+	return nil
+}
+
+// clearForm removes the form set by Data or FormStruct, with its
+// Content-Type, when another body replaces it.
+func clearForm(req *http.Request) {
+	if req.PostForm != nil && mediaType(req.Header.Get("Content-Type")) == AppFormURLEncoded {
+		req.Header.Del("Content-Type")
+	}
+	req.PostForm = nil
+}
+
 func setRequestBody(req *http.Request, body io.Reader) error {
 	rc, ok := body.(io.ReadCloser)
 	if !ok && body != nil {
 		rc = io.NopCloser(body)
 	}
 	req.Body = rc
+	clearForm(req)
+	req.ContentLength = 0
+	req.GetBody = nil
 	switch v := body.(type) {
 	case *bytes.Buffer:
 		req.ContentLength = int64(v.Len())
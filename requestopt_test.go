@@ -20,7 +20,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -115,6 +117,17 @@ func TestFile(t *testing.T) {
 	b, err := ioutil.ReadAll(req.Body)
 	assert.Equal(t, err, nil)
 	assert.Equal(t, b, fb)
+	assert.Nil(t, req.Body.Close())
+
+	body, err := req.GetBody()
+	assert.Equal(t, err, nil)
+	b, err = ioutil.ReadAll(body)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, b, fb)
+	assert.Nil(t, body.Close())
+
+	_, err = NewRequest("POST", "http://example.com", FileContent("testdata/missing"))
+	assert.True(t, os.IsNotExist(err))
 }
 
 func TestCookies(t *testing.T) {
@@ -150,7 +163,80 @@ func TestData(t *testing.T) {
 	for k, v := range f2 {
 		assert.Equal(t, req.PostForm.Get(k), v)
 	}
+	assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+	b, err := ioutil.ReadAll(req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "a=2&b=2", string(b))
+	assert.Equal(t, int64(len(b)), req.ContentLength)
+	body, err := req.GetBody()
+	assert.Nil(t, err)
+	b, _ = ioutil.ReadAll(body)
+	assert.Equal(t, "a=2&b=2", string(b))
 
+	// Another body in between is replaced.
+	req, err = NewRequest("POST", "http://example.com", Data(f1), Body(strings.NewReader("body")), Data(f2))
+	assert.Nil(t, err)
+	assert.Equal(t, "a=2&b=2", req.PostForm.Encode())
+	req, err = NewRequest("POST", "http://example.com", Data(f1), JSON(f2))
+	assert.Nil(t, err)
+	assert.Nil(t, req.PostForm)
+	assert.Equal(t, AppJSON, req.Header.Get("Content-Type"))
+}
+
+// echoServer responds with the Content-Type, Content-Length
+// and body of the requests.
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Content-Length", strconv.FormatInt(r.ContentLength, 10))
+		w.Write(b)
+	}))
+}
+
+func TestRequestOptions_Send(t *testing.T) {
+	ts := echoServer()
+	defer ts.Close()
+	file, _ := os.ReadFile("testdata/file4upload")
+
+	var testcases = []struct {
+		name        string
+		opts        []RequestOption
+		contentType string
+		body        string
+	}{
+		{"data", []RequestOption{Data(M{"a": "1", "b": "x y"})}, AppFormURLEncoded, "a=1&b=x+y"},
+		{"data merged", []RequestOption{Data(M{"a": "1"}), Data(M{"b": "2"})}, AppFormURLEncoded, "a=1&b=2"},
+		{"json", []RequestOption{JSON(M{"a": "1"})}, AppJSON, `{"a":"1"}`},
+		{"json after data", []RequestOption{Data(M{"a": "1"}), JSON(M{"a": "1"})}, AppJSON, `{"a":"1"}`},
+		{"body", []RequestOption{Body(strings.NewReader("plain"))}, "", "plain"},
+		{"body after data", []RequestOption{Data(M{"a": "1"}), Body(io.MultiReader(strings.NewReader("plain")))}, "", "plain"},
+		{"file after data", []RequestOption{Data(M{"a": "1"}), FileContent("testdata/file4upload")}, "", string(file)},
+		{"encode after data", []RequestOption{Data(M{"a": "1"}), Encode(AppFormURLEncoded, M{"b": "2"})}, AppFormURLEncoded, "b=2"},
+		{"file", []RequestOption{FileContent("testdata/file4upload")}, "", string(file)},
+		{"data after file", []RequestOption{FileContent("testdata/file4upload"), Data(M{"a": "1"})}, AppFormURLEncoded, "a=1"},
+	}
+	for _, tc := range testcases {
+		resp, err := Post(ts.URL, tc.opts...)
+		assert.Nil(t, err, tc.name)
+		txt, _ := resp.Text()
+		assert.Equal(t, tc.body, txt, tc.name)
+		assert.Equal(t, tc.contentType, resp.Header.Get("X-Content-Type"), tc.name)
+	}
+
+	f, err := os.Open("testdata/file4upload")
+	assert.Nil(t, err)
+	resp, err := Post(ts.URL, Data(M{"a": "1"}), MultipartForm(map[string]io.Reader{
+		"field": strings.NewReader("value"),
+		"file":  f,
+	}))
+	assert.Nil(t, err)
+	txt, _ := resp.Text()
+	assert.Contains(t, resp.Header.Get("X-Content-Type"), "multipart/form-data")
+	assert.Equal(t, strconv.Itoa(len(txt)), resp.Header.Get("X-Content-Length"))
+	assert.Contains(t, txt, "value")
+	assert.Contains(t, txt, string(file))
+	assert.NotContains(t, txt, "a=1")
 }
 
 func TestBody(t *testing.T) {
@@ -14,29 +14,67 @@
 package requests4go
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 )
 
+// ErrNotJSONContent is returned by JSON when the Content-Type of the response
+// is neither application/json nor a media type with the +json suffix.
 var ErrNotJSONContent = errors.New("content type not application/json")
 
+// ErrBodyTooLarge is returned when the response body exceeds MaxBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// ErrBodyConsumed is returned when reading a response body which was
+// already read without being cached, in streaming mode or by SaveContent.
+var ErrBodyConsumed = errors.New("response body already consumed")
+
 // Response is a wrapper of the http.Response.
 // It opens up new methods for http.Response.
 type Response struct {
 	// Embed an HTTP response directly. This makes a *http.Response act exactly
 	// like an *http.Response so that all meta methods are supported.
 	*http.Response
+
+	// Attempts is the number of attempts made to get the response.
+	Attempts int
+
+	// LenientJSON skips the Content-Type check of JSON.
+	LenientJSON bool
+
+	// MaxBodySize is the maximum number of bytes read by Content and the
+	// methods built on it, zero means no limit. Larger bodies fail with
+	// ErrBodyTooLarge.
+	MaxBodySize int64
+
+	// Streaming disables caching the body read by Content and the methods
+	// built on it, such as Text and JSON. The body can then be read only once,
+	// further reads fail with ErrBodyConsumed.
+	Streaming bool
+
+	// errorBody returns the value which error bodies are decoded to.
+	errorBody func() any
+
+	// codecs are the codecs of the session, used by Decode.
+	codecs map[string]Codec
+
+	content  []byte
+	cached   bool
+	consumed bool
 }
 
 // NewResponse returns new Response
 func NewResponse(resp *http.Response) *Response {
 	return &Response{
-		resp,
+		Response: resp,
+		Attempts: 1,
 	}
 }
 
@@ -45,15 +83,91 @@ func (r *Response) Ok() bool {
 	return r.StatusCode < 400 && r.StatusCode >= 200
 }
 
+// RaiseForStatus returns an *HTTPError if the status code is not OK, nil otherwise.
+//
+// The error keeps the beginning of the body, which can still be read
+// entirely from the response afterwards. JSON bodies are decoded into
+// HTTPError.Decoded, see WithErrorBody. If the body cannot be read, the
+// error is returned without it.
+func (r *Response) RaiseForStatus() error {
+	if r.Ok() {
+		return nil
+	}
+	e := &HTTPError{
+		StatusCode: r.StatusCode,
+		Status:     r.Status,
+		Header:     r.Header,
+	}
+	if r.Request != nil {
+		e.Method = r.Request.Method
+		if r.Request.URL != nil {
+			e.URL = r.Request.URL.String()
+		}
+	}
+	if r.Body == nil || r.consumed {
+		return e
+	}
+	mt := mediaType(r.Header.Get("Content-Type"))
+	content, err := r.errorContent(mt)
+	if err != nil {
+		return e
+	}
+	e.Body = content[:min(len(content), MaxErrorBodySnippet)]
+	e.Decoded = r.decodeErrorBody(mt, content)
+	return e
+}
+
+// errorContent returns the body of an error response of media type mt,
+// which stays readable from the response.
+func (r *Response) errorContent(mt string) ([]byte, error) {
+	if r.cached {
+		return r.content, nil
+	}
+
+	// Only read the beginning of the body, and put it back, so that
+	// a large error body is not buffered whole.
+	limit := int64(MaxErrorBodySnippet)
+	if isJSONMediaType(mt) {
+		limit = maxErrorBodyDecode
+	}
+	content, err := io.ReadAll(io.LimitReader(r.Body, limit))
+	if err != nil {
+		r.consumed = true
+		return nil, err
+	}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(content), r.Body), r.Body}
+	return content, nil
+}
+
+// decodeErrorBody decodes the JSON error body content of media type mt.
+func (r *Response) decodeErrorBody(mt string, content []byte) any {
+	var v any
+	switch {
+	case r.errorBody != nil && isJSONMediaType(mt):
+		v = r.errorBody()
+	case mt == AppProblemJSON:
+		v = &ProblemDetails{}
+	default:
+		return nil
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return nil
+	}
+	return v
+}
+
 // Close is to support io.ReadCloser.
 func (r *Response) Close() error {
+	if r.Body == nil {
+		return nil
+	}
 	_, err := io.Copy(ioutil.Discard, r)
 	if err != nil {
 		return err
 	}
-	if r.Body == nil {
-		return nil
-	}
 	return r.Body.Close()
 }
 
@@ -72,33 +186,99 @@ func (r *Response) Text() (string, error) {
 }
 
 // Content reads body of response and returns content of response in bytes.
+//
+// The content is cached, so Content can be called repeatedly, and the Body
+// of the response is replaced with a reader of the content, unless Streaming
+// is set.
 func (r *Response) Content() ([]byte, error) {
-	content, err := ioutil.ReadAll(r.Body)
+	if r.cached {
+		return r.content, nil
+	}
+	if r.consumed {
+		return nil, ErrBodyConsumed
+	}
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	var body io.Reader = r.Body
+	if r.MaxBodySize > 0 {
+		body = io.LimitReader(r.Body, r.MaxBodySize+1)
+	}
+	content, err := ioutil.ReadAll(body)
 	if err != nil && err != io.EOF {
+		r.consumed = true
 		return nil, err
 	}
+	if r.MaxBodySize > 0 && int64(len(content)) > r.MaxBodySize {
+		r.consumed = true
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, r.MaxBodySize)
+	}
+	if r.Streaming {
+		r.consumed = true
+		return content, nil
+	}
+
+	r.content, r.cached = content, true
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{bytes.NewReader(content), r.Body}
 	return content, nil
 }
 
 // SaveContent reads body of response and saves response body to file.
-func (r *Response) SaveContent(filename string) error {
+//
+// The body is streamed to the file, unless it was cached by Content,
+// and cannot be read again afterwards.
+//
+// The content is verified against sums, the checksums of the VerifyChecksum
+// option of the request and those of the Digest, Repr-Digest and Content-MD5
+// headers. On mismatch, the file is removed and a *ChecksumError returned.
+func (r *Response) SaveContent(filename string, sums ...Checksum) error {
+	if r.consumed {
+		return ErrBodyConsumed
+	}
+	sums = append(sums[:len(sums):len(sums)], transferOf(r.Request).checksums...)
+	sums = append(sums, headerChecksums(r.Response)...)
+	v, err := newVerifier(sums)
+	if err != nil {
+		return err
+	}
 	f, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	if _, err := io.Copy(f, r.Body); err != nil {
+	w := io.MultiWriter(f, v)
+	if r.cached {
+		_, err = w.Write(r.content)
+	} else {
+		r.consumed = true
+		_, err = io.Copy(w, r.Body)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := v.verify(); err != nil {
+		_ = os.Remove(filename)
 		return err
 	}
 	return nil
 }
 
 // JSON reads body of response and unmarshal the response content to v.
+//
+// The Content-Type must be application/json or a media type with the
+// +json suffix, such as application/problem+json, with any parameters.
+// Otherwise, JSON returns an error wrapping ErrNotJSONContent,
+// unless LenientJSON is set.
 func (r *Response) JSON(v interface{}) error {
-	ct := r.Header.Get("Content-Type")
-	if ct != AppJSON {
-		return errors.New("content type not application/json")
+	if err := r.checkJSON(); err != nil {
+		return err
 	}
 	content, err := r.Content()
 	if err != nil {
@@ -107,6 +287,18 @@ func (r *Response) JSON(v interface{}) error {
 	return json.Unmarshal(content, v)
 }
 
+// checkJSON checks the Content-Type of the response is JSON.
+func (r *Response) checkJSON() error {
+	if r.LenientJSON {
+		return nil
+	}
+	ct := r.Header.Get("Content-Type")
+	if !isJSONMediaType(mediaType(ct)) {
+		return fmt.Errorf("%w: got %q", ErrNotJSONContent, ct)
+	}
+	return nil
+}
+
 // XML unmarshal the response content as XML.
 func (r *Response) XML(v interface{}) error {
 	content, err := r.Content()
@@ -18,8 +18,11 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -220,8 +223,8 @@ func TestResponse_Content(t *testing.T) {
 			t.Errorf("#%s: Error = %v ", name, err)
 			continue
 		}
-		if len(p) != 0 {
-			t.Errorf("#%s: Body = %q want []", name, p)
+		if string(p) != tc.Body {
+			t.Errorf("#%s: Body = %q want %q", name, p, tc.Body)
 		}
 	}
 }
@@ -276,9 +279,37 @@ func TestResponse_JSON(t *testing.T) {
 				Close:         true,
 				ContentLength: -1,
 			},
-			Err:  errors.New("content type not application/json"),
+			Err:  ErrNotJSONContent,
+			Data: respData{},
+		},
+		"text content type": {
+			Raw: "HTTP/1.0 200 OK\r\n" +
+				"Connection: close\r\n" +
+				"Content-Type: text/plain\r\n" +
+				"\r\n" +
+				"{\"name\": \"foo\", \"age\": 10}\n",
+			Resp: http.Response{Request: dummyReq("GET")},
+			Err:  ErrNotJSONContent,
 			Data: respData{},
 		},
+		"json with charset": {
+			Raw: "HTTP/1.0 200 OK\r\n" +
+				"Connection: close\r\n" +
+				"Content-Type: Application/JSON; charset=utf-8\r\n" +
+				"\r\n" +
+				"{\"name\": \"foo\", \"age\": 10}\n",
+			Resp: http.Response{Request: dummyReq("GET")},
+			Data: respData{Name: "foo", Age: 10},
+		},
+		"structured suffix": {
+			Raw: "HTTP/1.0 200 OK\r\n" +
+				"Connection: close\r\n" +
+				"Content-Type: application/vnd.api+json\r\n" +
+				"\r\n" +
+				"{\"name\": \"foo\", \"age\": 10}\n",
+			Resp: http.Response{Request: dummyReq("GET")},
+			Data: respData{Name: "foo", Age: 10},
+		},
 		"json not valid": {
 			Raw: "HTTP/1.0 200 OK\r\n" +
 				"Connection: close\r\n" +
@@ -312,7 +343,17 @@ func TestResponse_JSON(t *testing.T) {
 		resp := NewResponse(hresp)
 		var data respData
 
-		if err := resp.JSON(&data); err != nil && err.Error() != tc.Err.Error() {
+		err = resp.JSON(&data)
+		switch {
+		case tc.Err == ErrNotJSONContent:
+			if !errors.Is(err, ErrNotJSONContent) {
+				t.Errorf("#%s: Error = %v want error = %s", name, err, tc.Err)
+			}
+		case tc.Err == nil:
+			if err != nil {
+				t.Errorf("#%s: Error = %v want no error", name, err)
+			}
+		case err == nil || err.Error() != tc.Err.Error():
 			t.Errorf("#%s: Error = %v want error = %s", name, err, tc.Err)
 		}
 
@@ -321,3 +362,172 @@ func TestResponse_JSON(t *testing.T) {
 		}
 	}
 }
+
+func TestResponse_RaiseForStatus(t *testing.T) {
+	long := strings.Repeat("x", MaxErrorBodySnippet+10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte("ok"))
+		case "/truncated":
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("cut"))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		case "/long":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(long))
+		default:
+			w.Header().Set("X-Reason", "missing")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL + "/ok")
+	assert.Nil(t, err)
+	assert.Nil(t, resp.RaiseForStatus())
+	_ = resp.Close()
+
+	resp, err = Get(ts.URL + "/missing")
+	assert.Nil(t, err)
+	err = resp.RaiseForStatus()
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.Equal(t, "GET", httpErr.Method)
+	assert.Equal(t, ts.URL+"/missing", httpErr.URL)
+	assert.Equal(t, "missing", httpErr.Header.Get("X-Reason"))
+	assert.Equal(t, "not found", string(httpErr.Body))
+	assert.Equal(t, "GET "+ts.URL+"/missing: 404 Not Found", err.Error())
+	text, _ := resp.Text()
+	assert.Equal(t, "not found", text)
+
+	resp, err = Get(ts.URL + "/long")
+	assert.Nil(t, err)
+	err = resp.RaiseForStatus()
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, long[:MaxErrorBodySnippet], string(httpErr.Body))
+	// Only the snippet is read, the rest of the body is left unread.
+	assert.False(t, resp.cached)
+	text, _ = resp.Text()
+	assert.Equal(t, long, text)
+
+	// The snippet is kept when the body is larger than MaxBodySize.
+	resp, err = NewSession(WithMaxBodySize(10)).Get(ts.URL + "/long")
+	assert.Nil(t, err)
+	err = resp.RaiseForStatus()
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, long[:MaxErrorBodySnippet], string(httpErr.Body))
+	b, err := io.ReadAll(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, long, string(b))
+
+	// The status is reported even if the body cannot be read.
+	_, err = NewSession(WithRaiseForStatus()).Get(ts.URL + "/truncated")
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Empty(t, httpErr.Body)
+}
+
+func TestResponse_LenientJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(`{"name": "foo", "age": 10}`))
+	}))
+	defer ts.Close()
+
+	var data respData
+	resp, err := Get(ts.URL)
+	assert.Nil(t, err)
+	err = resp.JSON(&data)
+	assert.ErrorIs(t, err, ErrNotJSONContent)
+	assert.Contains(t, err.Error(), `"text/plain"`)
+
+	resp, err = NewSession(WithLenientJSON()).Get(ts.URL)
+	assert.Nil(t, err)
+	assert.Nil(t, resp.JSON(&data))
+	assert.Equal(t, respData{Name: "foo", Age: 10}, data)
+}
+
+func TestResponse_CachedBody(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", AppJSON)
+		w.Write([]byte(`{"name": "foo", "age": 10}`))
+	}))
+	defer ts.Close()
+
+	resp, err := Get(ts.URL)
+	assert.Nil(t, err)
+	var data respData
+	assert.Nil(t, resp.JSON(&data))
+	assert.Equal(t, "foo", data.Name)
+	text, err := resp.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name": "foo", "age": 10}`, text)
+
+	// The body can be read again from the response too.
+	b, err := io.ReadAll(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, text, string(b))
+
+	filename := filepath.Join(t.TempDir(), "content")
+	assert.Nil(t, resp.SaveContent(filename))
+	b, _ = os.ReadFile(filename)
+	assert.Equal(t, text, string(b))
+	assert.Nil(t, resp.Close())
+
+	// SaveContent streams the body, which is consumed afterwards.
+	resp, err = Get(ts.URL)
+	assert.Nil(t, err)
+	assert.Nil(t, resp.SaveContent(filename))
+	_, err = resp.Text()
+	assert.ErrorIs(t, err, ErrBodyConsumed)
+	assert.Nil(t, resp.Close())
+}
+
+func TestResponse_Streaming(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	resp, err := NewSession(WithStreaming()).Get(ts.URL)
+	assert.Nil(t, err)
+	assert.True(t, resp.Streaming)
+	text, err := resp.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", text)
+	_, err = resp.Text()
+	assert.ErrorIs(t, err, ErrBodyConsumed)
+	_ = resp.Close()
+
+	resp, err = NewSession(WithMaxBodySize(5)).Get(ts.URL)
+	assert.Nil(t, err)
+	_, err = resp.Content()
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	_, err = resp.Content()
+	assert.ErrorIs(t, err, ErrBodyConsumed)
+	_ = resp.Close()
+
+	// RaiseForStatus puts back the beginning of a streamed body.
+	resp, err = NewSession(WithStreaming()).Get(ts.URL)
+	assert.Nil(t, err)
+	resp.StatusCode = http.StatusInternalServerError
+	var httpErr *HTTPError
+	assert.True(t, errors.As(resp.RaiseForStatus(), &httpErr))
+	assert.Equal(t, "0123456789", string(httpErr.Body))
+	text, err = resp.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", text)
+	_ = resp.Close()
+
+	resp, err = NewSession(WithMaxBodySize(10)).Get(ts.URL)
+	assert.Nil(t, err)
+	text, err = resp.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", text)
+	_ = resp.Close()
+}
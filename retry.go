@@ -0,0 +1,267 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryStatusCodes are the status codes retried when
+// RetryPolicy.StatusCodes is empty.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryMethods are the idempotent methods retried when
+// RetryPolicy.Methods is empty.
+var DefaultRetryMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// RetryPolicy configures automatic retries of requests.
+//
+// A request is only retried if its body can be rewound, that is
+// it has no body or its GetBody is set, like the bodies set by
+// JSON, FileContent, MultipartForm and Body with an in-memory reader.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry, it doubles after
+	// every attempt. Defaults to 100ms.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. Defaults to 10s.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of the delay which is randomized, from 0 to 1.
+	// A jitter of 0.5 picks a delay between 50% and 100% of the backoff.
+	Jitter float64
+
+	// StatusCodes are the retryable status codes.
+	// Defaults to DefaultRetryStatusCodes.
+	StatusCodes []int
+
+	// Methods are the retryable methods. Defaults to DefaultRetryMethods,
+	// requests with an Idempotency-Key header are then retryable too.
+	Methods []string
+
+	// RetryIf reports whether the result of an attempt should be retried.
+	// It replaces the checks on StatusCodes and errors, but not on Methods.
+	// By default, all errors except context cancellation and ErrCircuitOpen are retried.
+	RetryIf func(resp *Response, err error) bool
+
+	// RespectRetryAfter retries 429 and 503 responses telling when to retry,
+	// with a Retry-After header or with rate limit headers showing no request
+	// remains before the reset. The request then waits for the delay asked
+	// by the server, unless it exceeds MaxRetryAfter or the context deadline,
+	// in which case the response is returned.
+	RespectRetryAfter bool
+
+	// MaxRetryAfter is the longest delay accepted from the server.
+	// Zero means no limit other than the context deadline.
+	MaxRetryAfter time.Duration
+}
+
+// WithRetry enables automatic retries of the session requests.
+//
+// Retries happen after the session middlewares,
+// which only see the final response.
+func WithRetry(p RetryPolicy) SessionOption {
+	return func(s *Session) {
+		s.retry = &p
+	}
+}
+
+// Retry returns a Middleware retrying requests according to p.
+// The Attempts field of the returned Response records the number of attempts.
+func Retry(p RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*Response, error) {
+			return p.do(next, req)
+		})
+	}
+}
+
+func (p *RetryPolicy) do(next Doer, req *http.Request) (*Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			var err error
+			if r, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := next.Do(r)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+		if attempt >= p.MaxAttempts || !p.retryable(req, resp, err) {
+			return resp, err
+		}
+		delay := p.backoff(attempt)
+		if wait, ok := p.serverDelay(resp); ok {
+			if !p.acceptDelay(ctx, wait) {
+				return resp, err
+			}
+			delay = max(delay, wait)
+		}
+
+		if resp != nil {
+			_ = resp.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the attempt of req, which got resp and err, can be retried.
+func (p *RetryPolicy) retryable(req *http.Request, resp *Response, err error) bool {
+	if req.Context().Err() != nil || !rewindable(req) {
+		return false
+	}
+	// A middleware may return neither a response nor an error.
+	if resp == nil && err == nil {
+		return false
+	}
+	if !p.retryableMethod(req) {
+		return false
+	}
+	if _, ok := p.serverDelay(resp); ok {
+		return true
+	}
+	if p.RetryIf != nil {
+		return p.RetryIf(resp, err)
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrCircuitOpen)
+	}
+	codes := p.StatusCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryStatusCodes
+	}
+	return slices.Contains(codes, resp.StatusCode)
+}
+
+func (p *RetryPolicy) retryableMethod(req *http.Request) bool {
+	if len(p.Methods) != 0 {
+		return slices.Contains(p.Methods, req.Method)
+	}
+	if slices.Contains(DefaultRetryMethods, req.Method) {
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// serverDelay returns the delay asked by the server before retrying,
+// if RespectRetryAfter is set.
+func (p *RetryPolicy) serverDelay(resp *Response) (time.Duration, bool) {
+	if !p.RespectRetryAfter || resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	if d, ok := resp.RetryAfter(); ok {
+		return d, true
+	}
+	if rl, ok := resp.RateLimit(); ok && rl.Remaining == 0 && !rl.Reset.IsZero() {
+		return max(time.Until(rl.Reset), 0), true
+	}
+	return 0, false
+}
+
+// acceptDelay reports whether the request can wait for d.
+func (p *RetryPolicy) acceptDelay(ctx context.Context, d time.Duration) bool {
+	if p.MaxRetryAfter > 0 && d > p.MaxRetryAfter {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	return true
+}
+
+// backoff returns the delay after the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := p.MinBackoff, p.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = defaultMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	d := minBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(d))
+	}
+	return d
+}
+
+// rewindable reports whether the body of req can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
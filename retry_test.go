@@ -0,0 +1,217 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first n requests with status code.
+func flakyServer(n int32, code int) (*httptest.Server, *atomic.Int32, *[]string) {
+	var count atomic.Int32
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if count.Add(1) <= n {
+			w.WriteHeader(code)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	return ts, &count, &bodies
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func TestRetry(t *testing.T) {
+	ts, count, _ := flakyServer(2, http.StatusServiceUnavailable)
+	defer ts.Close()
+
+	s := NewSession(WithRetry(fastRetry))
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	text, _ := resp.Text()
+	assert.Equal(t, "ok", text)
+	assert.Equal(t, 3, resp.Attempts)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestRetry_GiveUp(t *testing.T) {
+	ts, count, _ := flakyServer(5, http.StatusBadGateway)
+	defer ts.Close()
+
+	s := NewSession(WithRetry(fastRetry))
+	resp, err := s.Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, 3, resp.Attempts)
+	assert.Equal(t, int32(3), count.Load())
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	var testcases = map[string]struct {
+		policy RetryPolicy
+		method string
+		opts   []RequestOption
+		status int
+		want   int32
+	}{
+		"status not retryable":  {fastRetry, "GET", nil, http.StatusNotFound, 1},
+		"method not idempotent": {fastRetry, "POST", nil, http.StatusServiceUnavailable, 1},
+		"idempotency key":       {fastRetry, "POST", []RequestOption{Headers(M{"Idempotency-Key": "k"})}, http.StatusServiceUnavailable, 3},
+		"custom methods":        {RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, Methods: []string{"POST"}}, "POST", nil, http.StatusServiceUnavailable, 2},
+		"custom status":         {RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond, StatusCodes: []int{http.StatusNotFound}}, "GET", nil, http.StatusNotFound, 2},
+		"non rewindable body":   {fastRetry, "PUT", []RequestOption{Body(io.MultiReader(strings.NewReader("x")))}, http.StatusServiceUnavailable, 1},
+	}
+	for name, tc := range testcases {
+		ts, count, _ := flakyServer(5, tc.status)
+		s := NewSession(WithRetry(tc.policy))
+		req, _ := s.NewRequest(tc.method, ts.URL, tc.opts...)
+		resp, err := s.Do(req)
+		assert.Nil(t, err, name)
+		_ = resp.Close()
+		assert.Equal(t, tc.want, count.Load(), name)
+		ts.Close()
+	}
+}
+
+func TestRetry_RewindBody(t *testing.T) {
+	ts, _, bodies := flakyServer(1, http.StatusServiceUnavailable)
+	defer ts.Close()
+
+	s := NewSession(WithRetry(fastRetry))
+	resp, err := s.Put(ts.URL, JSON(M{"a": "b"}))
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, []string{`{"a":"b"}`, `{"a":"b"}`}, *bodies)
+}
+
+func TestRetry_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	attempts := 0
+	p := fastRetry
+	p.RetryIf = func(resp *Response, err error) bool {
+		attempts++
+		return err != nil
+	}
+	_, err := NewSession(WithRetry(p)).Get(url)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestRetry_ContextCanceled(t *testing.T) {
+	ts, count, _ := flakyServer(5, http.StatusServiceUnavailable)
+	defer ts.Close()
+
+	s := NewSession(WithRetry(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := s.NewRequestWithContext(ctx, "GET", ts.URL)
+	_, err := s.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), count.Load())
+}
+
+func TestRetry_NilResponse(t *testing.T) {
+	// A middleware returning neither a response nor an error.
+	calls := 0
+	next := DoerFunc(func(req *http.Request) (*Response, error) {
+		calls++
+		return nil, nil
+	})
+	req, _ := NewRequest("GET", "http://example.com")
+	resp, err := fastRetry.do(next, req)
+	assert.Nil(t, resp)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(100))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(2)
+		assert.True(t, d > time.Second && d <= 2*time.Second, d)
+	}
+}
+
+func retryAfterServer(n int32, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) <= n {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	return ts, &count
+}
+
+func TestRetry_RetryAfter(t *testing.T) {
+	ts, count := retryAfterServer(1, "0")
+	defer ts.Close()
+
+	// 429 is not in StatusCodes, but the server told when to retry.
+	p := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, StatusCodes: []int{500}, RespectRetryAfter: true}
+	resp, err := NewSession(WithRetry(p)).Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), count.Load())
+}
+
+func TestRetry_RetryAfterTooLong(t *testing.T) {
+	ts, count := retryAfterServer(5, "120")
+	defer ts.Close()
+
+	p := RetryPolicy{MaxAttempts: 3, RespectRetryAfter: true, MaxRetryAfter: time.Second}
+	resp, err := NewSession(WithRetry(p)).Get(ts.URL)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	d, ok := resp.RetryAfter()
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+	assert.Equal(t, int32(1), count.Load())
+
+	// The context deadline is before the delay asked by the server.
+	p.MaxRetryAfter = 0
+	s := NewSession(WithRetry(p))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := s.NewRequestWithContext(ctx, "GET", ts.URL)
+	resp, err = s.Do(req)
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(2), count.Load())
+}
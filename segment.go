@@ -0,0 +1,221 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// errNoRanges reports a download cannot be split into segments.
+var errNoRanges = errors.New("byte ranges not supported")
+
+// segment is a byte range of a download, end included.
+type segment struct {
+	start, end int64
+}
+
+// downloadSegments downloads url to the file part in parallel segments,
+// if enabled by the Segments option and supported by the server.
+// It returns errNoRanges otherwise.
+func (s *Session) downloadSegments(ctx context.Context, url, part string, opts []RequestOption) error {
+	head, err := s.NewRequestWithContext(ctx, http.MethodHead, url, opts...)
+	if err != nil {
+		return err
+	}
+	cfg := transferOf(head)
+	if cfg.segments < 2 {
+		return errNoRanges
+	}
+	// Sizes are only meaningful without content coding.
+	head.Header.Set("Accept-Encoding", "identity")
+	resp, err := s.Do(head)
+	if err != nil {
+		return errNoRanges
+	}
+	_ = resp.Close()
+	size := resp.ContentLength
+	if !strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes") || size <= 0 {
+		return errNoRanges
+	}
+	validator := partMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}.validator()
+
+	// Segments cannot be resumed later, unlike a sequential partial download.
+	removePart(part)
+	d := &segmentedDownload{
+		s:         s,
+		url:       url,
+		opts:      opts,
+		cfg:       cfg,
+		validator: validator,
+		progress:  newProgress(cfg.progress, cfg.progressInterval, size),
+	}
+	err = d.fetch(ctx, part, size)
+	if err == nil {
+		// The segments are written out of order, so verify the file at once.
+		err = verifyFile(part, append(cfg.checksums, headerChecksums(resp.Response)...))
+	}
+	if err != nil {
+		removePart(part)
+	}
+	return err
+}
+
+// segmentedDownload is a download in parallel segments.
+type segmentedDownload struct {
+	s         *Session
+	url       string
+	opts      []RequestOption
+	cfg       transferConfig
+	validator string
+	progress  *progress
+
+	f       *os.File
+	written atomic.Int64
+}
+
+// fetch fetches the size bytes of the download concurrently, writing them
+// at their offsets into the preallocated file part.
+func (d *segmentedDownload) fetch(ctx context.Context, part string, size int64) error {
+	f, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		_ = f.Close()
+		return err
+	}
+	d.f = f
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	n := min(int64(d.cfg.segments), size)
+	length := (size + n - 1) / n
+	for start := int64(0); start < size; start += length {
+		seg := segment{start, min(start+length, size) - 1}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.download(ctx, seg); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := f.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	if got := d.written.Load(); got != size {
+		return fmt.Errorf("download: got %d bytes, want %d", got, size)
+	}
+	d.progress.finish()
+	return nil
+}
+
+// download fetches seg, resuming it up to MaxResumes times.
+func (d *segmentedDownload) download(ctx context.Context, seg segment) error {
+	for attempt := 0; ; attempt++ {
+		err := d.fetchSegment(ctx, &seg)
+		if err == nil {
+			return nil
+		}
+		var ierr *interruptedError
+		if !errors.As(err, &ierr) || ctx.Err() != nil || attempt >= d.cfg.maxResumes {
+			return err
+		}
+		if err := sleep(ctx, (&RetryPolicy{}).backoff(attempt+1)); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchSegment requests the range seg and writes it into the file,
+// advancing the start of seg by the number of bytes written.
+func (d *segmentedDownload) fetchSegment(ctx context.Context, seg *segment) (err error) {
+	req, err := d.s.NewRequestWithContext(ctx, http.MethodGet, d.url, d.opts...)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", seg.start, seg.end))
+	if d.validator != "" {
+		req.Header.Set("If-Range", d.validator)
+	}
+	resp, err := d.s.Do(req)
+	if err != nil {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return err
+		}
+		return &interruptedError{err}
+	}
+	defer func() {
+		// Do not read the rest of a body which is not wanted.
+		if err != nil {
+			_ = resp.Body.Close()
+			return
+		}
+		_ = resp.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, end, _, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != seg.start || end > seg.end {
+			return errRestart
+		}
+	case http.StatusOK:
+		// The server ignored the range, or the content changed.
+		return errRestart
+	default:
+		if err := resp.RaiseForStatus(); err != nil {
+			return err
+		}
+		return fmt.Errorf("download: unexpected status %q", resp.Status)
+	}
+
+	w := io.NewOffsetWriter(d.f, seg.start)
+	err = copyBody(io.MultiWriter(w, d.progress), io.LimitReader(resp.Body, seg.end-seg.start+1))
+	n, _ := w.Seek(0, io.SeekCurrent)
+	seg.start += n
+	d.written.Add(n)
+	if err != nil {
+		return err
+	}
+	if seg.start <= seg.end {
+		return &interruptedError{io.ErrUnexpectedEOF}
+	}
+	return nil
+}
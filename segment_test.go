@@ -0,0 +1,171 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// abortWriter aborts the response after n bytes of body.
+type abortWriter struct {
+	http.ResponseWriter
+	n int
+}
+
+func (w *abortWriter) Write(p []byte) (int, error) {
+	if len(p) >= w.n {
+		w.ResponseWriter.Write(p[:w.n])
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.n -= len(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// segmentServer serves downloadContent with byte ranges. The first response
+// to each range in fail is aborted half way.
+type segmentServer struct {
+	*httptest.Server
+	etag func(r *http.Request) string
+
+	mu       sync.Mutex
+	fail     map[string]bool
+	requests []string
+}
+
+func newSegmentServer(fail ...string) *segmentServer {
+	ss := &segmentServer{fail: map[string]bool{}}
+	for _, r := range fail {
+		ss.fail[r] = true
+	}
+	ss.etag = func(*http.Request) string { return `"v1"` }
+	ss.Server = httptest.NewServer(http.HandlerFunc(ss.serve))
+	return ss
+}
+
+func (ss *segmentServer) serve(w http.ResponseWriter, r *http.Request) {
+	rng := r.Header.Get("Range")
+	ss.mu.Lock()
+	ss.requests = append(ss.requests, r.Method+" "+rng)
+	fail := ss.fail[rng]
+	delete(ss.fail, rng)
+	ss.mu.Unlock()
+
+	w.Header().Set("ETag", ss.etag(r))
+	if fail {
+		w = &abortWriter{w, 100}
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(downloadContent))
+}
+
+func (ss *segmentServer) sortedRequests() []string {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	requests := append([]string(nil), ss.requests...)
+	sort.Strings(requests)
+	return requests
+}
+
+func TestSession_DownloadSegments(t *testing.T) {
+	const size = 1 << 20
+	ss := newSegmentServer("bytes=262144-524287")
+	defer ss.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ss.URL, path, Segments(4)))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, size, len(downloadContent))
+	assert.Equal(t, downloadContent, b)
+	assert.Equal(t, []string{
+		"GET bytes=0-262143",
+		"GET bytes=262144-524287",
+		"GET bytes=262244-524287",
+		"GET bytes=524288-786431",
+		"GET bytes=786432-1048575",
+		"HEAD ",
+	}, ss.sortedRequests())
+	assert.NoFileExists(t, path+partSuffix)
+}
+
+func TestSession_DownloadSegmentsFallback(t *testing.T) {
+	// The content changes after the HEAD request.
+	ss := newSegmentServer()
+	ss.etag = func(r *http.Request) string {
+		if r.Method == http.MethodHead {
+			return `"v0"`
+		}
+		return `"v1"`
+	}
+	defer ss.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ss.URL, path, Segments(2)))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+	assert.Contains(t, ss.sortedRequests(), "GET ")
+
+	// No ranges without the size.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Write(downloadContent)
+	}))
+	defer ts.Close()
+	path = filepath.Join(t.TempDir(), "chunked")
+	assert.Nil(t, NewSession().Download(ts.URL, path, Segments(2)))
+	b, _ = os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+}
+
+func TestSession_DownloadSegmentsIgnoredRange(t *testing.T) {
+	// The server advertises ranges, but ignores them. The bodies of the
+	// segment responses must not be read, the content is sent once.
+	var ignored atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+		if r.Method == http.MethodHead {
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(time.Second):
+				ignored.Add(1)
+			}
+		}
+		w.Write(downloadContent)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, NewSession().Download(ts.URL, path, Segments(4)))
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, downloadContent, b)
+	assert.Equal(t, int32(0), ignored.Load())
+}
@@ -14,94 +14,273 @@
 package requests4go
 
 import (
+	"context"
 	"golang.org/x/net/publicsuffix"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
+	"strings"
 )
 
 // Session allows user use cookies between HTTP requests.
 type Session struct {
 	Client *http.Client
+
+	baseURL     string
+	defaults    []RequestOption
+	middlewares []Middleware
+	retry       *RetryPolicy
+	limiter     *limiter
+	breaker     *breaker
+
+	raiseForStatus bool
+	errorBody      func() any
+	lenientJSON    bool
+	maxBodySize    int64
+	streaming      bool
+	codecs         map[string]Codec
+}
+
+// DefaultSession is the Session used by Get, Post, Do and the other
+// package level functions. It does not keep cookies.
+var DefaultSession = &Session{Client: &http.Client{}}
+
+// A SessionOption configures a Session.
+type SessionOption func(s *Session)
+
+// WithBaseURL sets the base URL of the session.
+//
+// A relative URL passed to the session is appended to the base URL,
+// so "users" and "/users" both become "https://example.com/api/users"
+// for the base "https://example.com/api". Absolute URLs are used as is.
+// The base URL may be a URI template, expanded along with the URL, see
+// PathParams.
+func WithBaseURL(base string) SessionOption {
+	return func(s *Session) {
+		s.baseURL = base
+	}
+}
+
+// WithDefaults adds options applied to every request of the session.
+//
+// Default options run before the options passed to each call,
+// so per-call options win: Headers and Params replace the default
+// values of the same key, Cookies replace default cookies of the same
+// name, and the last body option takes effect.
+func WithDefaults(opts ...RequestOption) SessionOption {
+	return func(s *Session) {
+		s.defaults = append(s.defaults, opts...)
+	}
+}
+
+// WithMiddleware adds middlewares to the session, see Session.Use.
+func WithMiddleware(mws ...Middleware) SessionOption {
+	return func(s *Session) {
+		s.Use(mws...)
+	}
+}
+
+// WithRaiseForStatus makes the session return an *HTTPError,
+// instead of the response, when the status code is not OK.
+func WithRaiseForStatus() SessionOption {
+	return func(s *Session) {
+		s.raiseForStatus = true
+	}
+}
+
+// WithErrorBody registers T as the type of the JSON error bodies of the session.
+//
+// When a response of the session with a JSON body has a status code
+// which is not OK, RaiseForStatus decodes the body into a new *T and sets
+// it as HTTPError.Decoded. If *T implements error, errors.As finds it
+// from the *HTTPError.
+func WithErrorBody[T any]() SessionOption {
+	return func(s *Session) {
+		s.errorBody = func() any { return new(T) }
+	}
+}
+
+// WithLenientJSON makes Response.JSON skip the Content-Type check
+// for the responses of the session.
+func WithLenientJSON() SessionOption {
+	return func(s *Session) {
+		s.lenientJSON = true
+	}
+}
+
+// WithMaxBodySize limits the size of the response bodies read by
+// Response.Content and the methods built on it, see Response.MaxBodySize.
+func WithMaxBodySize(n int64) SessionOption {
+	return func(s *Session) {
+		s.maxBodySize = n
+	}
+}
+
+// WithStreaming disables caching the response bodies of the session,
+// see Response.Streaming.
+func WithStreaming() SessionOption {
+	return func(s *Session) {
+		s.streaming = true
+	}
 }
 
 // NewSession returns a session struct.
-func NewSession() *Session {
-	return &Session{
+func NewSession(opts ...SessionOption) *Session {
+	s := &Session{
 		Client: &http.Client{
 			Jar: getDefaultJar(),
 		},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewRequest wrappers the NewRequestWithContext of session.
+func (s *Session) NewRequest(method, url string, opts ...RequestOption) (*http.Request, error) {
+	return s.NewRequestWithContext(context.Background(), method, url, opts...)
+}
+
+// NewRequestWithContext builds a new *http.Request like NewRequestWithContext,
+// resolving url against the base URL and applying the default options
+// of the session before opts.
+func (s *Session) NewRequestWithContext(ctx context.Context, method, url string, opts ...RequestOption) (*http.Request, error) {
+	all := make([]RequestOption, 0, len(s.defaults)+len(opts))
+	all = append(all, s.defaults...)
+	all = append(all, opts...)
+	return NewRequestWithContext(withCodecs(ctx, s.codecs), method, s.resolveURL(url), all...)
 }
 
 // Get sends a GET request, returns Response struct.
 func (s *Session) Get(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("GET", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("GET", url, opts...)
 }
 
 // Put sends a PUT request, returns Response struct.
 func (s *Session) Put(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("PUT", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("PUT", url, opts...)
 }
 
 // Post sends a POST request, returns Response struct.
 func (s *Session) Post(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("POST", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("POST", url, opts...)
 }
 
 // Delete sends a DELETE request, returns Response struct.
 func (s *Session) Delete(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("DELETE", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("DELETE", url, opts...)
 }
 
 // Patch sends a PATCH request, returns Response struct.
 func (s *Session) Patch(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("PATCH", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("PATCH", url, opts...)
 }
 
 // Head sends a HEAD request, returns Response struct.
 func (s *Session) Head(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("POST", url, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return s.do(req)
+	return s.request("HEAD", url, opts...)
 }
 
 // Options sends a OPTIONS request, returns Response struct.
 func (s *Session) Options(url string, opts ...RequestOption) (*Response, error) {
-	req, err := NewRequest("OPTIONS", url, opts...)
+	return s.request("OPTIONS", url, opts...)
+}
+
+// Use appends middlewares to the session.
+//
+// Middlewares run in the order they were added: the first one sees the
+// request first and the response last.
+// Use is not safe to call concurrently with sending requests.
+func (s *Session) Use(mws ...Middleware) {
+	s.middlewares = append(s.middlewares, mws...)
+}
+
+// Do sends the request through the middlewares of the session and returns the response.
+//
+// If the session was created WithRaiseForStatus, Do closes responses
+// with a status code which is not OK, and returns an *HTTPError.
+func (s *Session) Do(req *http.Request) (*Response, error) {
+	resp, err := s.doer().Do(req)
+	if err != nil || !s.raiseForStatus {
+		return resp, err
+	}
+	if err := resp.RaiseForStatus(); err != nil {
+		_ = resp.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// doer builds the chain sending the requests of the session.
+func (s *Session) doer() Doer {
+	var d Doer = DoerFunc(s.send)
+	if s.breaker != nil {
+		d = s.breaker.middleware(d)
+	}
+	if s.limiter != nil {
+		d = s.limiter.middleware(d)
+	}
+	if s.retry != nil {
+		d = Retry(*s.retry)(d)
+	}
+	return chain(d, s.middlewares)
+}
+
+func (s *Session) request(method, url string, opts ...RequestOption) (*Response, error) {
+	req, err := s.NewRequest(method, url, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return s.do(req)
+	return s.Do(req)
 }
 
-func (s *Session) do(req *http.Request) (*Response, error) {
-	resp, err := s.Client.Do(req)
+// send sends the request with the client of the session.
+func (s *Session) send(req *http.Request) (*Response, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if cfg := transferOf(req); cfg.progress != nil && req.Body != nil && req.Body != http.NoBody {
+		total := req.ContentLength
+		if total == 0 {
+			total = -1
+		}
+		req = req.WithContext(req.Context())
+		req.Body = &progressReader{req.Body, newProgress(cfg.progress, cfg.progressInterval, total)}
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	return NewResponse(resp), nil
+	return s.newResponse(resp), nil
+}
+
+// newResponse wraps resp with the response settings of the session.
+func (s *Session) newResponse(resp *http.Response) *Response {
+	r := NewResponse(resp)
+	r.errorBody = s.errorBody
+	r.LenientJSON = s.lenientJSON
+	r.MaxBodySize = s.maxBodySize
+	r.Streaming = s.streaming
+	r.codecs = s.codecs
+	return r
+}
+
+// resolveURL joins ref to the base URL unless ref is absolute.
+func (s *Session) resolveURL(ref string) string {
+	if s.baseURL == "" {
+		return ref
+	}
+	// Templates are absolute if their literal parts are.
+	_, stripped, _ := stripTemplate(ref)
+	if u, err := url.Parse(stripped); err == nil && u.IsAbs() {
+		return ref
+	}
+	if ref == "" {
+		return s.baseURL
+	}
+	return strings.TrimSuffix(s.baseURL, "/") + "/" + strings.TrimPrefix(ref, "/")
 }
 
 func getDefaultJar() *cookiejar.Jar {
@@ -4,4 +4,102 @@
 
 package requests4go
 
-// TODO(Kaiser925): add test for session
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSession_BaseURL(t *testing.T) {
+	var testcases = []struct {
+		base, ref, want string
+	}{
+		{"http://example.com/api", "users", "http://example.com/api/users"},
+		{"http://example.com/api/", "/users", "http://example.com/api/users"},
+		{"http://example.com/api", "users?a=1", "http://example.com/api/users?a=1"},
+		{"http://example.com/api", "", "http://example.com/api"},
+		{"http://example.com/api", "https://other.org/x", "https://other.org/x"},
+		{"", "http://example.com/x", "http://example.com/x"},
+	}
+	for _, tc := range testcases {
+		s := NewSession(WithBaseURL(tc.base))
+		req, err := s.NewRequest("GET", tc.ref)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.want, req.URL.String())
+	}
+}
+
+func TestSession_Defaults(t *testing.T) {
+	s := NewSession(WithDefaults(
+		Headers(M{"X-A": "default", "X-B": "default"}),
+		Params(M{"a": "default", "b": "default"}),
+		Cookies(M{"c1": "default", "c2": "default"}),
+		Auth("user", "default"),
+	))
+
+	req, err := s.NewRequest("GET", "http://example.com",
+		Headers(M{"X-B": "call"}),
+		Params(M{"b": "call"}),
+		Cookies(M{"c2": "call"}),
+		Auth("user", "call"),
+	)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "default", req.Header.Get("X-A"))
+	assert.Equal(t, "call", req.Header.Get("X-B"))
+	assert.Equal(t, "a=default&b=call", req.URL.RawQuery)
+
+	cookies := map[string]string{}
+	for _, c := range req.Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	assert.Equal(t, M{"c1": "default", "c2": "call"}, cookies)
+
+	_, password, _ := req.BasicAuth()
+	assert.Equal(t, "call", password)
+}
+
+func TestSession_Get(t *testing.T) {
+	var path, method, header string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, method, header = r.URL.Path, r.Method, r.Header.Get("X-Token")
+	}))
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL+"/v1"), WithDefaults(Headers(M{"X-Token": "t"})))
+	resp, err := s.Get("users")
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, "/v1/users", path)
+	assert.Equal(t, http.MethodGet, method)
+	assert.Equal(t, "t", header)
+
+	resp, err = s.Head("users")
+	assert.Nil(t, err)
+	_ = resp.Close()
+	assert.Equal(t, http.MethodHead, method)
+}
+
+func TestSession_RaiseForStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL), WithRaiseForStatus())
+	resp, err := s.Get("ok")
+	assert.Nil(t, err)
+	assert.True(t, resp.Ok())
+	_ = resp.Close()
+
+	resp, err = s.Delete("missing")
+	assert.Nil(t, resp)
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.Equal(t, http.MethodDelete, httpErr.Method)
+}
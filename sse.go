@@ -0,0 +1,258 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AppEventStream is a shortcut for "text/event-stream"
+	AppEventStream = "text/event-stream"
+
+	defaultSSERetry = 3 * time.Second
+	maxSSELineSize  = 16 << 20
+)
+
+// ErrNotEventStream is returned by EventSource when the response
+// is not a 200 OK text/event-stream.
+var ErrNotEventStream = errors.New("content type not text/event-stream")
+
+// Event is a server-sent event.
+type Event struct {
+	// ID is the last event ID when the event was dispatched.
+	ID string
+
+	// Event is the event type, empty for the default "message" type.
+	Event string
+
+	// Data is the data of the event, with multiple data lines joined by "\n".
+	Data string
+}
+
+// EventSource reads server-sent events from a URL with a Session,
+// reconnecting when the connection is lost.
+type EventSource struct {
+	// LastEventID is sent in the Last-Event-ID header when reconnecting.
+	// It is updated with the events received.
+	LastEventID string
+
+	// RetryDelay is the delay before reconnecting, updated by the retry
+	// field of the stream. Defaults to 3 seconds.
+	RetryDelay time.Duration
+
+	// MaxRetries is the number of consecutive failed reconnections
+	// before giving up, zero means no limit.
+	MaxRetries int
+
+	session *Session
+	url     string
+	opts    []RequestOption
+}
+
+// EventSource returns an EventSource reading events from url
+// with the session and options.
+func (s *Session) EventSource(url string, opts ...RequestOption) *EventSource {
+	return &EventSource{
+		RetryDelay: defaultSSERetry,
+		session:    s,
+		url:        url,
+		opts:       opts,
+	}
+}
+
+// Events connects to the event stream and iterates over its events.
+//
+// When the connection is lost, or cannot be established, Events waits for
+// RetryDelay and reconnects, sending the Last-Event-ID header. It stops
+// after yielding an error when the context is done, when MaxRetries is
+// reached, or when the server answers with an error status, returned as an
+// *HTTPError, or with something else than a 200 OK text/event-stream.
+// A 204 No Content response ends the iteration without error.
+func (es *EventSource) Events(ctx context.Context) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		failures := 0
+		for {
+			resp, err := es.connect(ctx)
+			switch {
+			case err == nil && resp.StatusCode == http.StatusNoContent:
+				_ = resp.Close()
+				return
+			case err == nil:
+				failures = 0
+				ok, readErr := es.read(resp.Body, yield)
+				_ = resp.Close()
+				if !ok {
+					return
+				}
+				err = readErr
+			case isPermanentSSEError(err):
+				yield(Event{}, err)
+				return
+			}
+
+			if ctx.Err() != nil {
+				yield(Event{}, ctx.Err())
+				return
+			}
+			if failures++; es.MaxRetries > 0 && failures > es.MaxRetries {
+				if err == nil {
+					err = io.ErrUnexpectedEOF
+				}
+				yield(Event{}, fmt.Errorf("event source: giving up after %d retries: %w", es.MaxRetries, err))
+				return
+			}
+			if err := sleep(ctx, es.RetryDelay); err != nil {
+				yield(Event{}, err)
+				return
+			}
+		}
+	}
+}
+
+// connect opens the event stream.
+func (es *EventSource) connect(ctx context.Context) (*Response, error) {
+	opts := append(es.opts[:len(es.opts):len(es.opts)], func(req *http.Request) error {
+		req.Header.Set("Accept", AppEventStream)
+		req.Header.Set("Cache-Control", "no-cache")
+		if es.LastEventID != "" {
+			req.Header.Set("Last-Event-ID", es.LastEventID)
+		}
+		return nil
+	})
+	req, err := es.session.NewRequestWithContext(ctx, http.MethodGet, es.url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := es.session.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+	if err := resp.RaiseForStatus(); err != nil {
+		_ = resp.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Close()
+		return nil, fmt.Errorf("%w: got status %q", ErrNotEventStream, resp.Status)
+	}
+	if mt := mediaType(resp.Header.Get("Content-Type")); mt != AppEventStream {
+		_ = resp.Close()
+		return nil, fmt.Errorf("%w: got %q", ErrNotEventStream, resp.Header.Get("Content-Type"))
+	}
+	return resp, nil
+}
+
+// isPermanentSSEError reports whether reconnecting after err is pointless.
+func isPermanentSSEError(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) || errors.Is(err, ErrNotEventStream)
+}
+
+// read parses the events of body and yields them.
+// It returns false if the iteration must stop, and the error which ended the stream.
+func (es *EventSource) read(body io.Reader, yield func(Event, error) bool) (bool, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 4096), maxSSELineSize)
+	scanner.Split(scanSSELines)
+
+	var event Event
+	var data strings.Builder
+	lastID := es.LastEventID
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// Dispatch the event.
+			es.LastEventID = lastID
+			if data.Len() == 0 {
+				event = Event{}
+				continue
+			}
+			event.ID = lastID
+			event.Data = strings.TrimSuffix(data.String(), "\n")
+			if !yield(event, nil) {
+				return false, nil
+			}
+			event = Event{}
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				// Clamp the delay to the largest duration.
+				ms = min(ms, uint64(math.MaxInt64/int64(time.Millisecond)))
+				es.RetryDelay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return true, scanner.Err()
+}
+
+// scanSSELines is a bufio.SplitFunc splitting lines ended by "\r\n", "\n" or "\r".
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// "\r", which may be followed by "\n".
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		return 0, nil, nil
+	}
+	if atEOF {
+		// An incomplete event at the end of the stream is discarded.
+		return len(data), nil, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,136 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestEventSource_Read(t *testing.T) {
+	stream := ": comment\n" +
+		"data: first\n" +
+		"\n" +
+		"event: update\r\n" +
+		"id: 1\r\n" +
+		"data:line one\r\n" +
+		"data: line two\r\n" +
+		"\r\n" +
+		"data\rdata:  spaced\r\r" +
+		"id: 2\nretry: 1500\n\n" +
+		"retry: soon\n" +
+		"unknown: field\n" +
+		"data: {\"a\": 1}\n\n" +
+		"data: incomplete"
+
+	es := &EventSource{RetryDelay: time.Second}
+	var events []Event
+	ok, err := es.read(iotest.OneByteReader(strings.NewReader(stream)), func(e Event, err error) bool {
+		events = append(events, e)
+		return true
+	})
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Equal(t, []Event{
+		{Data: "first"},
+		{ID: "1", Event: "update", Data: "line one\nline two"},
+		{ID: "1", Data: "\n spaced"},
+		{ID: "2", Data: `{"a": 1}`},
+	}, events)
+	assert.Equal(t, "2", es.LastEventID)
+	assert.Equal(t, 1500*time.Millisecond, es.RetryDelay)
+
+	// Too large delays are clamped instead of overflowing.
+	_, err = es.read(strings.NewReader("retry: 9223372036854775807\n\n"), func(Event, error) bool { return true })
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(math.MaxInt64/int64(time.Millisecond))*time.Millisecond, es.RetryDelay)
+}
+
+func TestEventSource_Reconnect(t *testing.T) {
+	var mu sync.Mutex
+	var lastIDs []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		n := len(lastIDs)
+		mu.Unlock()
+
+		assert.Equal(t, AppEventStream, r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		switch n {
+		case 1:
+			io.WriteString(w, "retry: 10\n\nid: 1\ndata: a\n\nid: 2\ndata: b\n\n")
+		case 2:
+			io.WriteString(w, "id: 3\ndata: c\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	es := NewSession(WithBaseURL(ts.URL)).EventSource("events")
+	var data []string
+	for e, err := range es.Events(context.Background()) {
+		assert.Nil(t, err)
+		data = append(data, e.Data)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, data)
+	assert.Equal(t, []string{"", "2", "3"}, lastIDs)
+	assert.Equal(t, 10*time.Millisecond, es.RetryDelay)
+}
+
+func TestEventSource_Errors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/text":
+			w.Write([]byte("data: x\n\n"))
+		}
+	}))
+	s := NewSession(WithBaseURL(ts.URL))
+
+	var httpErr *HTTPError
+	for _, err := range s.EventSource("missing").Events(context.Background()) {
+		assert.True(t, errors.As(err, &httpErr))
+	}
+	for _, err := range s.EventSource("text").Events(context.Background()) {
+		assert.ErrorIs(t, err, ErrNotEventStream)
+	}
+
+	ts.Close()
+	es := s.EventSource("closed")
+	es.RetryDelay = time.Millisecond
+	es.MaxRetries = 2
+	for _, err := range es.Events(context.Background()) {
+		assert.ErrorContains(t, err, "giving up after 2 retries")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	es.MaxRetries = 0
+	for _, err := range es.Events(ctx) {
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,307 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingPathParam is returned when building a request whose URL
+// template has a variable without value.
+var ErrMissingPathParam = errors.New("missing path parameter")
+
+// PathParams sets the variables of the RFC 6570 URI template of the request
+// URL, such as "/orgs/{org}/repos/{repo}" or "/search{?q,page}". It merges
+// with the variables of previous PathParams options.
+//
+// Templates of levels 1 to 3 are supported, with the prefix modifier of
+// level 4, as in "{name:3}". The values are percent-encoded as needed. All
+// the variables of the template must be set, otherwise building the request
+// fails with ErrMissingPathParam. URLs of requests without PathParams, and
+// URLs which are not valid templates, are used as is.
+func PathParams(params map[string]string) RequestOption {
+	return func(req *http.Request) error {
+		t, ok := req.Context().Value(templateKey{}).(*urlTemplate)
+		if !ok {
+			return nil
+		}
+		for k, v := range params {
+			t.vars[k] = v
+		}
+		t.used = true
+		return nil
+	}
+}
+
+type templateKey struct{}
+
+// urlTemplate is the URL template of a request, expanded once all the
+// options are applied.
+type urlTemplate struct {
+	raw   string
+	parts []templatePart
+
+	// query is the query of the URL without the expressions.
+	query string
+
+	vars map[string]string
+
+	// used reports whether PathParams set the variables.
+	used bool
+}
+
+// withTemplate returns the URL to parse for the request of the URL rawURL,
+// and the context carrying its template if it is one.
+func withTemplate(ctx context.Context, rawURL string) (context.Context, string) {
+	parts, stripped, ok := stripTemplate(rawURL)
+	if !ok {
+		return ctx, rawURL
+	}
+	u, err := url.Parse(stripped)
+	if err != nil {
+		return ctx, rawURL
+	}
+	t := &urlTemplate{raw: rawURL, parts: parts, query: u.RawQuery, vars: map[string]string{}}
+	return context.WithValue(ctx, templateKey{}, t), stripped
+}
+
+// stripTemplate returns the parts of the URI template s, and s without its
+// expressions, or false if s is not a template.
+func stripTemplate(s string) ([]templatePart, string, bool) {
+	if !strings.Contains(s, "{") {
+		return nil, s, false
+	}
+	parts, err := parseTemplate(s)
+	if err != nil {
+		return nil, s, false
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		if p.expr == nil {
+			b.WriteString(p.literal)
+		}
+	}
+	return parts, b.String(), true
+}
+
+// expandTemplate sets the URL of req to its expanded template, if any, or
+// else back to the raw URL without PathParams, keeping the query parameters
+// set by the options.
+func expandTemplate(req *http.Request) error {
+	t, ok := req.Context().Value(templateKey{}).(*urlTemplate)
+	if !ok {
+		return nil
+	}
+	expanded := t.raw
+	if t.used {
+		var err error
+		if expanded, err = t.expand(); err != nil {
+			return err
+		}
+	}
+	u, err := url.Parse(expanded)
+	if err != nil {
+		return err
+	}
+	if req.URL.RawQuery != t.query {
+		q := u.Query()
+		for k, vs := range req.URL.Query() {
+			q[k] = vs
+		}
+		u.RawQuery = q.Encode()
+	}
+	req.URL = u
+	req.Host = u.Host
+	return nil
+}
+
+// templatePart is a literal or an expression of a template.
+type templatePart struct {
+	literal string
+	expr    *templateExpr
+}
+
+type templateExpr struct {
+	op       byte
+	varspecs []varspec
+}
+
+type varspec struct {
+	name   string
+	prefix int
+}
+
+// parseTemplate parses the URI template s.
+func parseTemplate(s string) ([]templatePart, error) {
+	var parts []templatePart
+	for s != "" {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			parts = append(parts, templatePart{literal: s})
+			break
+		}
+		if s[i] == '}' {
+			return nil, fmt.Errorf("uri template: unexpected '}'")
+		}
+		if i > 0 {
+			parts = append(parts, templatePart{literal: s[:i]})
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("uri template: unclosed expression")
+		}
+		expr, err := parseExpr(s[i+1 : i+end])
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, templatePart{expr: expr})
+		s = s[i+end+1:]
+	}
+	return parts, nil
+}
+
+func parseExpr(s string) (*templateExpr, error) {
+	e := &templateExpr{}
+	if s != "" && strings.IndexByte("+#./;?&", s[0]) >= 0 {
+		e.op, s = s[0], s[1:]
+	}
+	for _, spec := range strings.Split(s, ",") {
+		var v varspec
+		name, prefix, hasPrefix := strings.Cut(strings.TrimSuffix(spec, "*"), ":")
+		if hasPrefix {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n <= 0 || n >= 10000 {
+				return nil, fmt.Errorf("uri template: invalid prefix in %q", spec)
+			}
+			v.prefix = n
+		}
+		if !validVarname(name) {
+			return nil, fmt.Errorf("uri template: invalid variable name %q", name)
+		}
+		v.name = name
+		e.varspecs = append(e.varspecs, v)
+	}
+	return e, nil
+}
+
+func validVarname(name string) bool {
+	if name == "" || name[0] == '.' || name[len(name)-1] == '.' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '_', c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// expand expands the template with its variables.
+func (t *urlTemplate) expand() (string, error) {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.expr == nil {
+			b.WriteString(p.literal)
+			continue
+		}
+		for _, v := range p.expr.varspecs {
+			if _, ok := t.vars[v.name]; !ok {
+				return "", fmt.Errorf("%w %q", ErrMissingPathParam, v.name)
+			}
+		}
+		p.expr.expand(&b, t.vars)
+	}
+	return b.String(), nil
+}
+
+// expand writes the expression expanded with vars, as in RFC 6570 section 3.2.
+func (e *templateExpr) expand(b *strings.Builder, vars map[string]string) {
+	first, sep, named, ifEmpty, reserved := "", ",", false, "", false
+	switch e.op {
+	case '+':
+		reserved = true
+	case '#':
+		first, reserved = "#", true
+	case '.':
+		first, sep = ".", "."
+	case '/':
+		first, sep = "/", "/"
+	case ';':
+		first, sep, named = ";", ";", true
+	case '?':
+		first, sep, named, ifEmpty = "?", "&", true, "="
+	case '&':
+		first, sep, named, ifEmpty = "&", "&", true, "="
+	}
+	for i, v := range e.varspecs {
+		if i == 0 {
+			b.WriteString(first)
+		} else {
+			b.WriteString(sep)
+		}
+		value := vars[v.name]
+		if v.prefix > 0 {
+			if r := []rune(value); len(r) > v.prefix {
+				value = string(r[:v.prefix])
+			}
+		}
+		if named {
+			b.WriteString(v.name)
+			if value == "" {
+				b.WriteString(ifEmpty)
+				continue
+			}
+			b.WriteByte('=')
+		}
+		b.WriteString(escapeTemplateValue(value, reserved))
+	}
+}
+
+// escapeTemplateValue percent-encodes s, except the unreserved characters,
+// and the reserved characters and percent-encoded triplets if reserved is set.
+func escapeTemplateValue(s string, reserved bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isUnreserved(c),
+			reserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0,
+			reserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
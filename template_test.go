@@ -0,0 +1,138 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Examples of RFC 6570 section 3.2.
+var templateVars = map[string]string{
+	"var":   "value",
+	"hello": "Hello World!",
+	"path":  "/foo/bar",
+	"empty": "",
+	"x":     "1024",
+	"y":     "768",
+}
+
+func TestURLTemplate(t *testing.T) {
+	var testcases = []struct {
+		template string
+		expected string
+	}{
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"map?{x,y}", "map?1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"{#path,x}/here", "#/foo/bar,1024/here"},
+		{"X{.var}", "X.value"},
+		{"X{.x,y}", "X.1024.768"},
+		{"{/var}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+		{"{var:3}", "val"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{?var:3}", "?var=val"},
+	}
+	for _, tc := range testcases {
+		parts, err := parseTemplate(tc.template)
+		assert.Nil(t, err, tc.template)
+		got, err := (&urlTemplate{parts: parts, vars: templateVars}).expand()
+		assert.Nil(t, err, tc.template)
+		assert.Equal(t, tc.expected, got, tc.template)
+	}
+
+	for _, s := range []string{"{", "}", "{}", "{a b}", "{var:0}", `{"a":1}`} {
+		_, err := parseTemplate(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	req, err := NewRequest("GET", "https://example.com/orgs/{org}/repos/{repo}{?page}",
+		PathParams(map[string]string{"org": "a/b", "page": "2"}),
+		Params(M{"sort": "name"}),
+		PathParams(map[string]string{"repo": "x y"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/orgs/a%2Fb/repos/x%20y?page=2&sort=name", req.URL.String())
+	assert.Equal(t, "example.com", req.Host)
+
+	req, err = NewRequest("GET", "https://{host}/x", PathParams(map[string]string{"host": "example.com"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/x", req.URL.String())
+	assert.Equal(t, "example.com", req.Host)
+
+	_, err = NewRequest("GET", "https://example.com/orgs/{org}/repos/{repo}",
+		PathParams(map[string]string{"org": "a"}))
+	assert.True(t, errors.Is(err, ErrMissingPathParam))
+	assert.Contains(t, err.Error(), `"repo"`)
+
+	// Without PathParams, the URL is used as is.
+	req, err = NewRequest("GET", "http://example.com/files/{id}.txt", Params(M{"a": "1"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "/files/{id}.txt", req.URL.Path)
+	assert.Equal(t, "a=1", req.URL.RawQuery)
+	req, err = NewRequest("GET", "http://example.com/?filter={f}")
+	assert.Nil(t, err)
+	assert.Equal(t, "{f}", req.URL.Query().Get("filter"))
+	_, err = NewRequest("GET", "https://{host}/x")
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrMissingPathParam))
+
+	// Not a template.
+	req, err = NewRequest("GET", `https://example.com/?filter={"a":1}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1}`, req.URL.Query().Get("filter"))
+}
+
+func TestSession_PathParams(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RequestURI()))
+	}))
+	defer ts.Close()
+
+	s := NewSession(
+		WithBaseURL(ts.URL+"/api/{version}"),
+		WithDefaults(PathParams(map[string]string{"version": "v1"})),
+	)
+	resp, err := s.Get("/users/{id}", PathParams(map[string]string{"id": "ä"}))
+	assert.Nil(t, err)
+	txt, _ := resp.Text()
+	assert.Equal(t, "/api/v1/users/%C3%A4", txt)
+
+	_, err = s.Get("/users/{id}")
+	assert.True(t, errors.Is(err, ErrMissingPathParam))
+
+	resp, err = s.Get("http://{+host}/x", PathParams(map[string]string{"host": ts.Listener.Addr().String(), "version": "v2"}))
+	assert.Nil(t, err)
+	txt, _ = resp.Text()
+	assert.Equal(t, "/x", txt)
+}
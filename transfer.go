@@ -0,0 +1,75 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const defaultMaxResumes = 5
+
+// transferConfig holds the settings of the transfer options,
+// carried by the context of the request.
+type transferConfig struct {
+	maxResumes int
+	segments   int
+	checksums  []Checksum
+
+	progress         ProgressFunc
+	progressInterval time.Duration
+}
+
+type transferKey struct{}
+
+// MaxResumes sets how many times Session.Download resumes an interrupted
+// download, 5 by default. It has no effect on other requests.
+func MaxResumes(n int) RequestOption {
+	return func(req *http.Request) error {
+		setTransfer(req, func(c *transferConfig) {
+			c.maxResumes = n
+		})
+		return nil
+	}
+}
+
+// Segments makes Session.Download fetch the content in n parallel segments
+// with Range requests, when the server accepts byte ranges and reports the
+// size in response to a HEAD request. It has no effect on other requests.
+func Segments(n int) RequestOption {
+	return func(req *http.Request) error {
+		setTransfer(req, func(c *transferConfig) {
+			c.segments = n
+		})
+		return nil
+	}
+}
+
+// setTransfer updates the transfer config of req with fn.
+func setTransfer(req *http.Request, fn func(c *transferConfig)) {
+	c := transferOf(req)
+	fn(&c)
+	*req = *req.WithContext(context.WithValue(req.Context(), transferKey{}, &c))
+}
+
+// transferOf returns a copy of the transfer config of req.
+func transferOf(req *http.Request) transferConfig {
+	if req != nil {
+		if c, ok := req.Context().Value(transferKey{}).(*transferConfig); ok {
+			return *c
+		}
+	}
+	return transferConfig{maxResumes: defaultMaxResumes}
+}
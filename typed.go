@@ -0,0 +1,80 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"net/http"
+)
+
+// DoJSON sends a request with the session s, or DefaultSession if s is nil,
+// and decodes the JSON body of the response into a T.
+//
+// The body of the returned response is closed. If the status code is not OK,
+// DoJSON returns the *HTTPError of RaiseForStatus. Responses to HEAD requests
+// and 204 No Content responses are not decoded. The Accept header is set to
+// application/json unless an option sets it.
+func DoJSON[T any](ctx context.Context, s *Session, method, url string, opts ...RequestOption) (T, *Response, error) {
+	var v T
+	if s == nil {
+		s = DefaultSession
+	}
+	req, err := s.NewRequestWithContext(ctx, method, url, append(opts[:len(opts):len(opts)], acceptJSON)...)
+	if err != nil {
+		return v, nil, err
+	}
+	resp, err := s.Do(req)
+	if err != nil {
+		return v, resp, err
+	}
+	defer resp.Close()
+
+	if err := resp.RaiseForStatus(); err != nil {
+		return v, resp, err
+	}
+	if method == http.MethodHead || resp.StatusCode == http.StatusNoContent {
+		return v, resp, nil
+	}
+	if err := resp.JSON(&v); err != nil {
+		return v, resp, err
+	}
+	return v, resp, nil
+}
+
+// GetJSON sends a GET request and decodes the JSON response into a T, see DoJSON.
+func GetJSON[T any](url string, opts ...RequestOption) (T, *Response, error) {
+	return DoJSON[T](context.Background(), nil, http.MethodGet, url, opts...)
+}
+
+// PostJSON sends body as JSON in a POST request
+// and decodes the JSON response into a Resp, see DoJSON.
+func PostJSON[Req, Resp any](url string, body Req, opts ...RequestOption) (Resp, *Response, error) {
+	opts = append([]RequestOption{JSON(body)}, opts...)
+	return DoJSON[Resp](context.Background(), nil, http.MethodPost, url, opts...)
+}
+
+// PutJSON sends body as JSON in a PUT request
+// and decodes the JSON response into a Resp, see DoJSON.
+func PutJSON[Req, Resp any](url string, body Req, opts ...RequestOption) (Resp, *Response, error) {
+	opts = append([]RequestOption{JSON(body)}, opts...)
+	return DoJSON[Resp](context.Background(), nil, http.MethodPut, url, opts...)
+}
+
+// acceptJSON sets the Accept header to application/json if it is not set.
+func acceptJSON(req *http.Request) error {
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", AppJSON)
+	}
+	return nil
+}
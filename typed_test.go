@@ -0,0 +1,93 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func jsonServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Header().Set("Content-Type", AppJSON)
+			w.Header().Set("X-Accept", r.Header.Get("Accept"))
+			w.Write([]byte(`{"name": "foo", "age": 10}`))
+		case "/echo":
+			var data respData
+			_ = json.NewDecoder(r.Body).Decode(&data)
+			data.Age++
+			w.Header().Set("Content-Type", AppJSON)
+			_ = json.NewEncoder(w).Encode(data)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetJSON(t *testing.T) {
+	ts := jsonServer()
+	defer ts.Close()
+
+	data, resp, err := GetJSON[respData](ts.URL + "/user")
+	assert.Nil(t, err)
+	assert.Equal(t, respData{Name: "foo", Age: 10}, data)
+	assert.Equal(t, AppJSON, resp.Header.Get("X-Accept"))
+
+	m, resp, err := GetJSON[map[string]any](ts.URL+"/user", Headers(M{"Accept": "*/*"}))
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", m["name"])
+	assert.Equal(t, "*/*", resp.Header.Get("X-Accept"))
+
+	_, resp, err = GetJSON[respData](ts.URL + "/missing")
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestPostJSON(t *testing.T) {
+	ts := jsonServer()
+	defer ts.Close()
+
+	data, _, err := PostJSON[respData, respData](ts.URL+"/echo", respData{Name: "bar", Age: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, respData{Name: "bar", Age: 2}, data)
+
+	data, _, err = PutJSON[respData, respData](ts.URL+"/echo", respData{Name: "baz"})
+	assert.Nil(t, err)
+	assert.Equal(t, respData{Name: "baz", Age: 1}, data)
+}
+
+func TestDoJSON(t *testing.T) {
+	ts := jsonServer()
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL))
+	data, _, err := DoJSON[respData](context.Background(), s, "GET", "user")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo", data.Name)
+
+	data, resp, err := DoJSON[respData](context.Background(), s, "DELETE", "empty")
+	assert.Nil(t, err)
+	assert.Equal(t, respData{}, data)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
@@ -13,10 +13,36 @@
 
 package requests4go
 
+import (
+	"mime"
+	"strings"
+)
+
 // M is a shortcut for map[string]string
 type M = map[string]string
 
 const (
 	// AppJSON is a shortcut for "application/json"
 	AppJSON = "application/json"
+
+	// AppProblemJSON is a shortcut for "application/problem+json"
+	AppProblemJSON = "application/problem+json"
+
+	// AppFormURLEncoded is a shortcut for "application/x-www-form-urlencoded"
+	AppFormURLEncoded = "application/x-www-form-urlencoded"
 )
+
+// mediaType returns the lower cased media type of a Content-Type header value,
+// or an empty string if it is invalid.
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
+
+// isJSONMediaType reports whether mt is application/json or has the +json suffix.
+func isJSONMediaType(mt string) bool {
+	return mt == AppJSON || strings.HasSuffix(mt, "+json")
+}
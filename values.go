@@ -0,0 +1,408 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SliceStyle is the encoding of slices to url values.
+type SliceStyle int
+
+const (
+	// SliceRepeat repeats the key for each element: a=1&a=2.
+	SliceRepeat SliceStyle = iota
+	// SliceComma joins the elements with commas: a=1,2.
+	SliceComma
+	// SliceBrackets repeats the key with brackets: a[]=1&a[]=2.
+	SliceBrackets
+)
+
+// ValuesMarshaler is the interface implemented by types
+// that can encode themselves to url values under key.
+type ValuesMarshaler interface {
+	MarshalValues(key string, values url.Values) error
+}
+
+var (
+	valuesMarshalerType = reflect.TypeFor[ValuesMarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// ValuesEncoder encodes structs to url values.
+//
+// The fields are encoded under the name of their url tag, or else their
+// name. The tag may be followed by comma separated options:
+//
+//	Name  string    `url:"name,omitempty"`   // omitted if empty
+//	Tags  []string  `url:"tags,comma"`       // slice style: repeat, comma or brackets
+//	Draft bool      `url:"draft,int"`        // 1 or 0
+//	Since time.Time `url:"since,unix"`       // unix or unixmilli timestamp
+//	Day   time.Time `url:"day" layout:"2006-01-02"`
+//	Skip  string    `url:"-"`
+//
+// Nested structs and maps are encoded with brackets, as in user[name], and
+// the fields of embedded structs without tag as fields of the parent.
+// Values implementing ValuesMarshaler encode themselves, and those
+// implementing encoding.TextMarshaler are encoded as text.
+type ValuesEncoder struct {
+	// SliceStyle is the style of slices without style option.
+	SliceStyle SliceStyle
+
+	// TimeLayout is the layout of times without layout tag,
+	// time.RFC3339 if empty.
+	TimeLayout string
+}
+
+// EncodeValues encodes v to url values with the default ValuesEncoder.
+func EncodeValues(v any) (url.Values, error) {
+	return ValuesEncoder{}.Encode(v)
+}
+
+// QueryStruct sets url query parameters for the request from v, see Query.
+func QueryStruct(v any) RequestOption {
+	return ValuesEncoder{}.Query(v)
+}
+
+// FormStruct sets a form-encoded request body from v, see Form.
+func FormStruct(v any) RequestOption {
+	return ValuesEncoder{}.Form(v)
+}
+
+// Query sets url query parameters for the request from v, a struct,
+// a map or url.Values. It replaces any existing values.
+func (e ValuesEncoder) Query(v any) RequestOption {
+	return func(req *http.Request) error {
+		values, err := e.Encode(v)
+		if err != nil {
+			return err
+		}
+		q := req.URL.Query()
+		for k, vs := range values {
+			q[k] = vs
+		}
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// Form sets a form-encoded request body from v, a struct,
+// a map or url.Values.
+func (e ValuesEncoder) Form(v any) RequestOption {
+	return func(req *http.Request) error {
+		values, err := e.Encode(v)
+		if err != nil {
+			return err
+		}
+		return setFormBody(req, values)
+	}
+}
+
+// Encode encodes v, a struct, a map or url.Values, to url values.
+func (e ValuesEncoder) Encode(v any) (url.Values, error) {
+	values := make(url.Values)
+	switch v := v.(type) {
+	case nil:
+		return values, nil
+	case url.Values:
+		for k, vs := range v {
+			values[k] = slices.Clone(vs)
+		}
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("url values: cannot encode %T", v)
+	}
+	if err := e.encode(values, "", rv, tagOptions{}); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// tagOptions are the options of the url tag of a field.
+type tagOptions struct {
+	omitEmpty bool
+	style     *SliceStyle
+	int       bool
+	unix      bool
+	unixMilli bool
+	layout    string
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	name, rest, _ := strings.Cut(tag, ",")
+	var opts tagOptions
+	for _, opt := range strings.Split(rest, ",") {
+		style := SliceStyle(-1)
+		switch opt {
+		case "omitempty":
+			opts.omitEmpty = true
+		case "int":
+			opts.int = true
+		case "unix":
+			opts.unix = true
+		case "unixmilli":
+			opts.unixMilli = true
+		case "repeat":
+			style = SliceRepeat
+		case "comma":
+			style = SliceComma
+		case "brackets":
+			style = SliceBrackets
+		}
+		if style >= 0 {
+			opts.style = &style
+		}
+	}
+	return name, opts
+}
+
+func (e ValuesEncoder) encode(values url.Values, key string, rv reflect.Value, opts tagOptions) error {
+	for {
+		// A marshaler may be behind pointers or interfaces.
+		if m, ok := implements(rv, valuesMarshalerType); ok {
+			return m.Interface().(ValuesMarshaler).MarshalValues(key, values)
+		}
+		if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface {
+			break
+		}
+		if rv.IsNil() {
+			values.Add(key, "")
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if s, ok, err := e.scalar(rv, opts); ok || err != nil {
+		if err == nil {
+			values.Add(key, s)
+		}
+		return err
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return e.encodeStruct(values, key, rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("url values: cannot encode %s", rv.Type())
+		}
+		keys := rv.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		for _, k := range keys {
+			if err := e.encode(values, joinKey(key, k.String()), rv.MapIndex(k), tagOptions{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		return e.encodeSlice(values, key, rv, opts)
+	}
+	return fmt.Errorf("url values: cannot encode %s", rv.Type())
+}
+
+func (e ValuesEncoder) encodeStruct(values url.Values, prefix string, rv reflect.Value) error {
+	t := rv.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("url")
+		if tag == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+		name, opts := parseTag(tag)
+		opts.layout = sf.Tag.Get("layout")
+		fv := rv.Field(i)
+		if opts.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			// Embedded struct, encoded as fields of the parent.
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := e.encodeStruct(values, prefix, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if err := e.encode(values, joinKey(prefix, name), fv, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e ValuesEncoder) encodeSlice(values url.Values, key string, rv reflect.Value, opts tagOptions) error {
+	style := e.SliceStyle
+	if opts.style != nil {
+		style = *opts.style
+	}
+	if style == SliceComma {
+		parts := make([]string, rv.Len())
+		for i := range rv.Len() {
+			s, ok, err := e.scalar(indirect(rv.Index(i)), opts)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("url values: cannot encode %s with commas", rv.Type())
+			}
+			parts[i] = s
+		}
+		values.Add(key, strings.Join(parts, ","))
+		return nil
+	}
+	elemKey := key
+	if style == SliceBrackets {
+		elemKey = key + "[]"
+	}
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+		k := elemKey
+		if _, ok, _ := e.scalar(indirect(elem), opts); !ok {
+			// Compound values are indexed, as in a[0][b].
+			k = fmt.Sprintf("%s[%d]", key, i)
+		}
+		if err := e.encode(values, k, elem, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scalar returns the url value of rv, and false if rv is not a scalar.
+func (e ValuesEncoder) scalar(rv reflect.Value, opts tagOptions) (string, bool, error) {
+	if !rv.IsValid() {
+		return "", true, nil
+	}
+	if rv.Type() == timeType {
+		t := rv.Interface().(time.Time)
+		switch {
+		case opts.unix:
+			return strconv.FormatInt(t.Unix(), 10), true, nil
+		case opts.unixMilli:
+			return strconv.FormatInt(t.UnixMilli(), 10), true, nil
+		case opts.layout != "":
+			return t.Format(opts.layout), true, nil
+		case e.TimeLayout != "":
+			return t.Format(e.TimeLayout), true, nil
+		}
+		return t.Format(time.RFC3339), true, nil
+	}
+	if m, ok := implements(rv, textMarshalerType); ok {
+		b, err := m.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), true, err
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), true, nil
+	case reflect.Bool:
+		if opts.int {
+			if rv.Bool() {
+				return "1", true, nil
+			}
+			return "0", true, nil
+		}
+		return strconv.FormatBool(rv.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()), true, nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes()), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// implements returns rv, or its address, if it implements the interface t.
+func implements(rv reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !rv.IsValid() {
+		return rv, false
+	}
+	if rv.Type().Implements(t) {
+		if (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && rv.IsNil() {
+			return rv, false
+		}
+		return rv, true
+	}
+	if rv.CanAddr() && reflect.PointerTo(rv.Type()).Implements(t) {
+		return rv.Addr(), true
+	}
+	return rv, false
+}
+
+// indirect dereferences the pointers and interfaces of rv.
+func indirect(rv reflect.Value) reflect.Value {
+	for (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	}
+	if m, ok := implements(rv, reflect.TypeFor[interface{ IsZero() bool }]()); ok {
+		return m.Interface().(interface{ IsZero() bool }).IsZero()
+	}
+	return rv.IsZero()
+}
+
+// joinKey returns the key name nested in prefix.
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
@@ -0,0 +1,185 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type pageParams struct {
+	Page    int  `url:"page"`
+	PerPage int  `url:"per_page,omitempty"`
+	Draft   bool `url:"draft,int"`
+}
+
+type coords struct {
+	Lat, Lng float64
+}
+
+// MarshalValues encodes the coordinates as "lat,lng".
+func (c coords) MarshalValues(key string, values url.Values) error {
+	values.Set(key, strconv.FormatFloat(c.Lat, 'f', -1, 64)+","+strconv.FormatFloat(c.Lng, 'f', -1, 64))
+	return nil
+}
+
+type searchParams struct {
+	pageParams
+	Query   string            `url:"q"`
+	Tags    []string          `url:"tags,comma,omitempty"`
+	IDs     []int             `url:"id"`
+	Kinds   []string          `url:"kind,brackets"`
+	Since   time.Time         `url:"since,unix"`
+	Day     time.Time         `url:"day" layout:"2006-01-02"`
+	Updated time.Time         `url:"updated,omitempty"`
+	Owner   *owner            `url:"owner"`
+	Labels  map[string]string `url:"labels"`
+	Near    coords            `url:"near"`
+	IP      net.IP            `url:"ip,omitempty"`
+	Limit   *int              `url:"limit,omitempty"`
+	Secret  string            `url:"-"`
+	Score   float64
+	hidden  string
+}
+
+type owner struct {
+	Name  string `url:"name"`
+	Email string `url:"email,omitempty"`
+}
+
+func TestEncodeValues(t *testing.T) {
+	day := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	values, err := EncodeValues(&searchParams{
+		pageParams: pageParams{Page: 2, Draft: true},
+		Query:      "go http",
+		Tags:       []string{"a", "b"},
+		IDs:        []int{1, 2},
+		Kinds:      []string{"x", "y"},
+		Since:      day,
+		Day:        day,
+		Owner:      &owner{Name: "kaiser"},
+		Labels:     map[string]string{"env": "prod", "app": "web"},
+		Near:       coords{1.5, -2},
+		IP:         net.ParseIP("127.0.0.1"),
+		Secret:     "secret",
+		Score:      0.5,
+		hidden:     "hidden",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{
+		"page":        {"2"},
+		"draft":       {"1"},
+		"q":           {"go http"},
+		"tags":        {"a,b"},
+		"id":          {"1", "2"},
+		"kind[]":      {"x", "y"},
+		"since":       {strconv.FormatInt(day.Unix(), 10)},
+		"day":         {"2026-10-18"},
+		"owner[name]": {"kaiser"},
+		"labels[app]": {"web"},
+		"labels[env]": {"prod"},
+		"near":        {"1.5,-2"},
+		"ip":          {"127.0.0.1"},
+		"Score":       {"0.5"},
+	}, values)
+
+	values, err = EncodeValues(url.Values{"a": {"1", "2"}})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"a": {"1", "2"}}, values)
+
+	// Nil interfaces and pointers.
+	values, err = EncodeValues(struct {
+		C  ValuesMarshaler `url:"c"`
+		D  ValuesMarshaler `url:"d,omitempty"`
+		E  any             `url:"e"`
+		P  *coords         `url:"p"`
+		OK ValuesMarshaler `url:"ok"`
+	}{OK: coords{1, 2}})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"c": {""}, "e": {""}, "p": {""}, "ok": {"1,2"}}, values)
+
+	// Marshalers behind interfaces.
+	values, err = EncodeValues(struct {
+		A any   `url:"a"`
+		L []any `url:"l"`
+	}{A: coords{1, 2}, L: []any{&coords{3, 4}}})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"a": {"1,2"}, "l[0]": {"3,4"}}, values)
+
+	_, err = EncodeValues(42)
+	assert.NotNil(t, err)
+	_, err = EncodeValues(struct {
+		C chan int `url:"c"`
+	}{})
+	assert.NotNil(t, err)
+}
+
+func TestValuesEncoder(t *testing.T) {
+	type item struct {
+		Name string `url:"name"`
+	}
+	e := ValuesEncoder{SliceStyle: SliceBrackets, TimeLayout: time.DateOnly}
+	values, err := e.Encode(struct {
+		IDs   []int     `url:"id"`
+		Seq   []int     `url:"seq,repeat"`
+		Items []item    `url:"items"`
+		At    time.Time `url:"at"`
+	}{
+		IDs:   []int{1, 2},
+		Seq:   []int{3},
+		Items: []item{{"a"}, {"b"}},
+		At:    time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{
+		"id[]":           {"1", "2"},
+		"seq":            {"3"},
+		"items[0][name]": {"a"},
+		"items[1][name]": {"b"},
+		"at":             {"2026-10-18"},
+	}, values)
+}
+
+func TestQueryStruct(t *testing.T) {
+	req, err := NewRequest("GET", "http://example.com/?page=1&keep=1", QueryStruct(pageParams{Page: 3}))
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"page": {"3"}, "draft": {"0"}, "keep": {"1"}}, req.URL.Query())
+}
+
+func TestFormStruct(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Write([]byte(r.PostForm.Encode()))
+	}))
+	defer ts.Close()
+
+	resp, err := Post(ts.URL, FormStruct(pageParams{Page: 3, PerPage: 10}))
+	assert.Nil(t, err)
+	txt, _ := resp.Text()
+	assert.Equal(t, "draft=0&page=3&per_page=10", txt)
+
+	req, err := NewRequest("POST", ts.URL, FormStruct(url.Values{"a": {"1", "2"}}))
+	assert.Nil(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(req.Body)
+	assert.Equal(t, "a=1&a=2", string(b))
+	assert.Equal(t, int64(len(b)), req.ContentLength)
+}
@@ -0,0 +1,702 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	defaultMaxMessageSize = 32 << 20
+	compressThreshold     = 256
+	deflateWindow         = 32 << 10
+	closeTimeout          = 5 * time.Second
+	maxControlPayload     = 125
+)
+
+// MessageType is the type of a WebSocket data message.
+type MessageType int
+
+const (
+	// TextMessage is a UTF-8 text message.
+	TextMessage MessageType = 1
+	// BinaryMessage is a binary message.
+	BinaryMessage MessageType = 2
+)
+
+// WebSocket close codes, see RFC 6455 section 7.4.1.
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseInternalServerErr       = 1011
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// ErrBadHandshake is returned when the server does not complete the WebSocket handshake.
+var ErrBadHandshake = errors.New("websocket: bad handshake")
+
+// ErrWebSocketClosed is returned when writing to a WebSocket after it was closed.
+var ErrWebSocketClosed = errors.New("websocket: connection closed")
+
+// CloseError is returned by ReadMessage once the peer closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: close %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: close %d: %s", e.Code, e.Reason)
+}
+
+// protocolError is a violation of the WebSocket protocol by the peer.
+type protocolError struct {
+	code int
+	msg  string
+}
+
+func (e *protocolError) Error() string {
+	return "websocket: " + e.msg
+}
+
+// WebSocket is a WebSocket connection, see Session.WebSocket.
+//
+// ReadMessage must be called from a single goroutine at a time, it answers
+// pings and close frames of the peer. Writes may be called concurrently.
+type WebSocket struct {
+	// Response is the response of the opening handshake.
+	// Its body is the connection, which must not be used directly.
+	Response *Response
+
+	// MaxMessageSize is the maximum size of a received message,
+	// after decompression. Defaults to 32 MiB.
+	MaxMessageSize int64
+
+	// FragmentSize splits the messages written into fragments of at most
+	// FragmentSize bytes. Zero means messages are sent in a single frame.
+	FragmentSize int
+
+	// PongHandler is called with the payload of the pongs received.
+	PongHandler func(data []byte)
+
+	rwc    io.ReadWriteCloser
+	br     *bufio.Reader
+	client bool
+
+	// compress is set when permessage-deflate was negotiated.
+	compress bool
+	// readContextTakeover is set when the peer compresses messages with
+	// the context of the previous ones.
+	readContextTakeover bool
+	readDict            []byte
+
+	readMu   sync.Mutex
+	readErr  error
+	done     chan struct{}
+	doneOnce sync.Once
+
+	writeMu   sync.Mutex
+	bw        *bufio.Writer
+	closeSent bool
+}
+
+// WebSocket opens a WebSocket connection to url, see WebSocketWithContext.
+func (s *Session) WebSocket(url string, opts ...RequestOption) (*WebSocket, error) {
+	return s.WebSocketWithContext(context.Background(), url, opts...)
+}
+
+// WebSocketWithContext opens a WebSocket connection to url with the opening
+// handshake of RFC 6455, offering per-message deflate compression.
+//
+// The handshake request is built like the other requests of the session,
+// with its base URL, default options, opts, cookies and client, through its
+// middlewares. The ws and wss schemes are used as http and https. The client
+// is only used to open the connection: ctx and the client timeout do not
+// apply to the connection once open. A subprotocol can be requested with
+// the Sec-WebSocket-Protocol header.
+//
+// If the server does not switch protocols, the error is the *HTTPError
+// of the response, or wraps ErrBadHandshake.
+func (s *Session) WebSocketWithContext(ctx context.Context, url string, opts ...RequestOption) (*WebSocket, error) {
+	p := make([]byte, 16)
+	if _, err := rand.Read(p); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(p)
+
+	opts = append(opts[:len(opts):len(opts)], func(req *http.Request) error {
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Sec-WebSocket-Key", key)
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		if req.Header.Get("Sec-WebSocket-Extensions") == "" {
+			req.Header.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_no_context_takeover")
+		}
+		return nil
+	})
+	req, err := s.NewRequestWithContext(ctx, http.MethodGet, websocketHTTPURL(url), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := *s
+	ws.Client = websocketClient(s.Client)
+	ws.raiseForStatus = false
+	resp, err := ws.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		err := resp.RaiseForStatus()
+		_ = resp.Close()
+		if err == nil {
+			err = fmt.Errorf("%w: unexpected status %q", ErrBadHandshake, resp.Status)
+		}
+		return nil, err
+	}
+
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Close()
+		return nil, fmt.Errorf("%w: connection not writable", ErrBadHandshake)
+	}
+	if err := checkHandshake(resp.Header, key); err != nil {
+		_ = rwc.Close()
+		return nil, err
+	}
+
+	c := newWebSocket(rwc, true)
+	c.Response = resp
+	if ext, ok := parseDeflateExtension(resp.Header.Values("Sec-WebSocket-Extensions")); ok {
+		c.compress = true
+		c.readContextTakeover = !ext["server_no_context_takeover"]
+	}
+	return c, nil
+}
+
+// Subprotocol returns the subprotocol selected by the server, if any.
+func (c *WebSocket) Subprotocol() string {
+	if c.Response == nil {
+		return ""
+	}
+	return c.Response.Header.Get("Sec-WebSocket-Protocol")
+}
+
+// ReadMessage reads the next data message, reassembling its fragments
+// and decompressing it.
+//
+// Pings are answered and pongs are passed to PongHandler while waiting for
+// a message. Once the peer closed the connection, ReadMessage answers the
+// close frame and returns a *CloseError.
+func (c *WebSocket) ReadMessage() (MessageType, []byte, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+	return c.readMessage()
+}
+
+func (c *WebSocket) readMessage() (MessageType, []byte, error) {
+	if c.readErr != nil {
+		return 0, nil, c.readErr
+	}
+	maxSize := c.MaxMessageSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxMessageSize
+	}
+
+	var typ MessageType
+	var message []byte
+	var compressed, started bool
+	for {
+		f, err := readFrame(c.br, maxSize-int64(len(message)))
+		if err != nil {
+			return 0, nil, c.fail(err)
+		}
+		if f.masked == c.client {
+			return 0, nil, c.fail(&protocolError{CloseProtocolError, "invalid frame masking"})
+		}
+		if f.rsv1 && (!c.compress || f.opcode == opContinuation || f.opcode >= opClose) {
+			return 0, nil, c.fail(&protocolError{CloseProtocolError, "unexpected RSV1 bit"})
+		}
+
+		switch f.opcode {
+		case opPing:
+			if err := c.writeControl(opPong, f.payload); err != nil && !errors.Is(err, ErrWebSocketClosed) {
+				return 0, nil, c.fail(err)
+			}
+			continue
+		case opPong:
+			if c.PongHandler != nil {
+				c.PongHandler(f.payload)
+			}
+			continue
+		case opClose:
+			return 0, nil, c.closeReceived(f.payload)
+		case opText, opBinary:
+			if started {
+				return 0, nil, c.fail(&protocolError{CloseProtocolError, "new message before the end of the previous one"})
+			}
+			typ, compressed, started = MessageType(f.opcode), f.rsv1, true
+		case opContinuation:
+			if !started {
+				return 0, nil, c.fail(&protocolError{CloseProtocolError, "continuation frame without message"})
+			}
+		default:
+			return 0, nil, c.fail(&protocolError{CloseProtocolError, fmt.Sprintf("unknown opcode %d", f.opcode)})
+		}
+
+		message = append(message, f.payload...)
+		if f.fin {
+			break
+		}
+	}
+
+	if compressed {
+		var err error
+		if message, err = c.inflate(message, maxSize); err != nil {
+			return 0, nil, c.fail(err)
+		}
+	}
+	if typ == TextMessage && !utf8.Valid(message) {
+		return 0, nil, c.fail(&protocolError{CloseInvalidFramePayloadData, "invalid UTF-8 in text message"})
+	}
+	return typ, message, nil
+}
+
+// WriteMessage writes a data message, compressed if per-message deflate was
+// negotiated and the message is large enough, and fragmented if FragmentSize is set.
+func (c *WebSocket) WriteMessage(typ MessageType, data []byte) error {
+	if typ != TextMessage && typ != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", typ)
+	}
+	compressed := c.compress && len(data) >= compressThreshold
+	if compressed {
+		var err error
+		if data, err = deflate(data); err != nil {
+			return err
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return ErrWebSocketClosed
+	}
+	f := frame{opcode: byte(typ), rsv1: compressed}
+	for {
+		f.payload = data
+		if c.FragmentSize > 0 && len(data) > c.FragmentSize {
+			f.payload = data[:c.FragmentSize]
+		}
+		data = data[len(f.payload):]
+		f.fin = len(data) == 0
+		if err := writeFrame(c.bw, f, c.client); err != nil {
+			return err
+		}
+		if f.fin {
+			return c.bw.Flush()
+		}
+		f.opcode, f.rsv1 = opContinuation, false
+	}
+}
+
+// WriteText writes a text message.
+func (c *WebSocket) WriteText(text string) error {
+	return c.WriteMessage(TextMessage, []byte(text))
+}
+
+// Ping sends a ping with data, of at most 125 bytes.
+// The pong is passed to PongHandler by ReadMessage.
+func (c *WebSocket) Ping(data []byte) error {
+	return c.writeControl(opPing, data)
+}
+
+// Close closes the connection with the normal closure code, see CloseWithCode.
+func (c *WebSocket) Close() error {
+	return c.CloseWithCode(CloseNormalClosure, "")
+}
+
+// CloseWithCode performs the closing handshake: it sends a close frame with
+// code and reason, waits for the close frame of the peer, for at most
+// 5 seconds, then closes the connection.
+//
+// If ReadMessage is running in another goroutine, it receives the close
+// frame of the peer, otherwise CloseWithCode reads and discards the
+// messages received until then.
+func (c *WebSocket) CloseWithCode(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	err := c.writeControl(opClose, payload)
+	if errors.Is(err, ErrWebSocketClosed) {
+		err = nil
+	}
+
+	timer := time.AfterFunc(closeTimeout, func() { _ = c.rwc.Close() })
+	defer timer.Stop()
+	if c.readMu.TryLock() {
+		for c.readErr == nil {
+			_, _, _ = c.readMessage()
+		}
+		c.readMu.Unlock()
+	} else {
+		// The reader may return a message received before the close
+		// frame, and not read any further.
+		wait := time.NewTimer(closeTimeout)
+		defer wait.Stop()
+		select {
+		case <-c.done:
+		case <-wait.C:
+		}
+	}
+	_ = c.rwc.Close()
+	return err
+}
+
+func newWebSocket(rwc io.ReadWriteCloser, client bool) *WebSocket {
+	return &WebSocket{
+		rwc:    rwc,
+		br:     bufio.NewReader(rwc),
+		bw:     bufio.NewWriter(rwc),
+		client: client,
+		done:   make(chan struct{}),
+	}
+}
+
+// writeControl writes a control frame.
+func (c *WebSocket) writeControl(opcode byte, payload []byte) error {
+	if len(payload) > maxControlPayload {
+		return fmt.Errorf("websocket: control frame payload larger than %d bytes", maxControlPayload)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return ErrWebSocketClosed
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+	if err := writeFrame(c.bw, frame{fin: true, opcode: opcode, payload: payload}, c.client); err != nil {
+		return err
+	}
+	return c.bw.Flush()
+}
+
+// closeReceived handles the close frame of the peer.
+func (c *WebSocket) closeReceived(payload []byte) error {
+	e := &CloseError{Code: CloseNoStatusReceived}
+	switch {
+	case len(payload) == 1:
+		return c.fail(&protocolError{CloseProtocolError, "invalid close frame"})
+	case len(payload) >= 2:
+		e.Code = int(binary.BigEndian.Uint16(payload))
+		e.Reason = string(payload[2:])
+		if !utf8.ValidString(e.Reason) {
+			return c.fail(&protocolError{CloseInvalidFramePayloadData, "invalid UTF-8 in close reason"})
+		}
+		payload = payload[:2]
+	}
+	// Echo the status code, as the close frame answer.
+	_ = c.writeControl(opClose, payload)
+	c.setReadErr(e)
+	_ = c.rwc.Close()
+	return e
+}
+
+// fail records a read error, closing the connection
+// with a close frame first on protocol errors.
+func (c *WebSocket) fail(err error) error {
+	var perr *protocolError
+	if errors.As(err, &perr) {
+		payload := make([]byte, 2)
+		binary.BigEndian.PutUint16(payload, uint16(perr.code))
+		_ = c.writeControl(opClose, payload)
+		_ = c.rwc.Close()
+	}
+	c.setReadErr(err)
+	return err
+}
+
+func (c *WebSocket) setReadErr(err error) {
+	c.readErr = err
+	c.doneOnce.Do(func() { close(c.done) })
+}
+
+// deflateTail ends a message compressed with per-message deflate:
+// the removed empty stored block and a final empty stored block.
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff}
+
+// inflate decompresses a message compressed with per-message deflate.
+func (c *WebSocket) inflate(p []byte, maxSize int64) ([]byte, error) {
+	r := flate.NewReaderDict(io.MultiReader(bytes.NewReader(p), bytes.NewReader(deflateTail)), c.readDict)
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, &protocolError{CloseInvalidFramePayloadData, "invalid compressed message: " + err.Error()}
+	}
+	if int64(len(out)) > maxSize {
+		return nil, &protocolError{CloseMessageTooBig, "message too big"}
+	}
+	if c.readContextTakeover {
+		c.readDict = append(c.readDict, out...)
+		if len(c.readDict) > deflateWindow {
+			c.readDict = append([]byte(nil), c.readDict[len(c.readDict)-deflateWindow:]...)
+		}
+	}
+	return out, nil
+}
+
+// deflate compresses a message with per-message deflate, without context takeover.
+func deflate(p []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w, err := flate.NewWriter(&b, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(p); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), deflateTail[:4]), nil
+}
+
+// frame is a WebSocket frame.
+type frame struct {
+	fin     bool
+	rsv1    bool
+	masked  bool
+	opcode  byte
+	payload []byte
+}
+
+// readFrame reads a frame, unmasking its payload.
+// Data frame payloads larger than maxSize fail.
+func readFrame(br *bufio.Reader, maxSize int64) (frame, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(br, h[:]); err != nil {
+		return frame{}, err
+	}
+	f := frame{
+		fin:    h[0]&0x80 != 0,
+		rsv1:   h[0]&0x40 != 0,
+		opcode: h[0] & 0x0f,
+		masked: h[1]&0x80 != 0,
+	}
+	if h[0]&0x30 != 0 {
+		return frame{}, &protocolError{CloseProtocolError, "unexpected RSV2 or RSV3 bit"}
+	}
+
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err := io.ReadFull(br, b[:]); err != nil {
+			return frame{}, err
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err := io.ReadFull(br, b[:]); err != nil {
+			return frame{}, err
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+	if f.opcode >= opClose && (n > maxControlPayload || !f.fin) {
+		return frame{}, &protocolError{CloseProtocolError, "invalid control frame"}
+	}
+	if f.opcode < opClose && n > uint64(max(maxSize, 0)) {
+		return frame{}, &protocolError{CloseMessageTooBig, "message too big"}
+	}
+
+	var key [4]byte
+	if f.masked {
+		if _, err := io.ReadFull(br, key[:]); err != nil {
+			return frame{}, err
+		}
+	}
+	f.payload = make([]byte, n)
+	if _, err := io.ReadFull(br, f.payload); err != nil {
+		return frame{}, err
+	}
+	if f.masked {
+		maskBytes(key, f.payload)
+	}
+	return f, nil
+}
+
+// writeFrame writes a frame, masking its payload with a random key if mask is set.
+func writeFrame(w io.Writer, f frame, mask bool) error {
+	h := make([]byte, 2, 14)
+	h[0] = f.opcode
+	if f.fin {
+		h[0] |= 0x80
+	}
+	if f.rsv1 {
+		h[0] |= 0x40
+	}
+	n := len(f.payload)
+	switch {
+	case n <= 125:
+		h[1] = byte(n)
+	case n <= 0xffff:
+		h[1] = 126
+		h = binary.BigEndian.AppendUint16(h, uint16(n))
+	default:
+		h[1] = 127
+		h = binary.BigEndian.AppendUint64(h, uint64(n))
+	}
+
+	payload := f.payload
+	if mask {
+		h[1] |= 0x80
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		h = append(h, key[:]...)
+		payload = append([]byte(nil), payload...)
+		maskBytes(key, payload)
+	}
+	if _, err := w.Write(h); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func maskBytes(key [4]byte, p []byte) {
+	for i := range p {
+		p[i] ^= key[i&3]
+	}
+}
+
+// acceptKey returns the Sec-WebSocket-Accept value for key.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// checkHandshake checks the response headers of the opening handshake.
+func checkHandshake(h http.Header, key string) error {
+	if !strings.EqualFold(h.Get("Upgrade"), "websocket") {
+		return fmt.Errorf("%w: missing Upgrade: websocket", ErrBadHandshake)
+	}
+	if !headerContainsToken(h, "Connection", "upgrade") {
+		return fmt.Errorf("%w: missing Connection: Upgrade", ErrBadHandshake)
+	}
+	if h.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return fmt.Errorf("%w: invalid Sec-WebSocket-Accept", ErrBadHandshake)
+	}
+	return nil
+}
+
+// headerContainsToken reports whether the comma separated header contains token.
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseDeflateExtension returns the parameters of the permessage-deflate
+// extension accepted by the server, if any.
+func parseDeflateExtension(values []string) (map[string]bool, bool) {
+	for _, v := range values {
+		for _, ext := range strings.Split(v, ",") {
+			params := strings.Split(ext, ";")
+			if strings.TrimSpace(params[0]) != "permessage-deflate" {
+				continue
+			}
+			m := make(map[string]bool)
+			for _, p := range params[1:] {
+				name, _, _ := strings.Cut(strings.TrimSpace(p), "=")
+				m[name] = true
+			}
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// websocketHTTPURL replaces the ws and wss schemes of url with http and https.
+func websocketHTTPURL(url string) string {
+	switch {
+	case len(url) >= 5 && strings.EqualFold(url[:5], "ws://"):
+		return "http://" + url[5:]
+	case len(url) >= 6 && strings.EqualFold(url[:6], "wss://"):
+		return "https://" + url[6:]
+	}
+	return url
+}
+
+// websocketClient returns a copy of c without timeout,
+// which only speaks HTTP/1.1 as required by the opening handshake.
+func websocketClient(c *http.Client) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	wc := *c
+	wc.Timeout = 0
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if t, ok := transport.(*http.Transport); ok {
+		t = t.Clone()
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		t.Protocols = protocols
+		wc.Transport = t
+	}
+	return &wc
+}
@@ -0,0 +1,277 @@
+// Developed by Kaiser925 on 2026/10/18.
+// Lasted modified 2026/10/18.
+// Copyright (c) 2026.  All rights reserved
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requests4go
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// websocketServer accepts WebSocket connections and passes them to handle.
+func websocketServer(compress bool, handle func(c *WebSocket, r *http.Request)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		compress := compress && strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n")
+		if compress {
+			brw.WriteString("Sec-WebSocket-Extensions: permessage-deflate; server_no_context_takeover\r\n")
+		}
+		brw.WriteString("\r\n")
+		brw.Flush()
+
+		c := newWebSocket(conn, false)
+		c.br = brw.Reader
+		c.compress = compress
+		handle(c, r)
+	}))
+}
+
+// echo sends back the messages received until the connection is closed.
+func echo(c *WebSocket, _ *http.Request) {
+	for {
+		typ, p, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := c.WriteMessage(typ, p); err != nil {
+			return
+		}
+	}
+}
+
+func wsURL(ts *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(ts.URL, "http")
+}
+
+func TestWebSocket_Echo(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		ts := websocketServer(compress, echo)
+
+		c, err := NewSession().WebSocket(wsURL(ts))
+		assert.Nil(t, err)
+		assert.Equal(t, compress, c.compress)
+
+		large := bytes.Repeat([]byte("compressible "), 1000)
+		c.FragmentSize = 100
+		assert.Nil(t, c.WriteText("hello"))
+		assert.Nil(t, c.WriteMessage(BinaryMessage, []byte{0, 1, 2}))
+		assert.Nil(t, c.WriteMessage(BinaryMessage, large))
+
+		typ, p, err := c.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, TextMessage, typ)
+		assert.Equal(t, "hello", string(p))
+		typ, p, err = c.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, BinaryMessage, typ)
+		assert.Equal(t, []byte{0, 1, 2}, p)
+		_, p, err = c.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, large, p)
+
+		start := time.Now()
+		assert.Nil(t, c.Close())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.ErrorIs(t, c.WriteText("closed"), ErrWebSocketClosed)
+		ts.Close()
+	}
+}
+
+func TestWebSocket_Session(t *testing.T) {
+	var got *http.Request
+	ts := websocketServer(false, func(c *WebSocket, r *http.Request) {
+		got = r
+		_ = c.CloseWithCode(CloseGoingAway, "bye")
+	})
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL), WithDefaults(Auth("user", "pass"), Cookies(M{"session": "s1"})))
+	c, err := s.WebSocket("chat", Headers(M{"Sec-WebSocket-Protocol": "chat"}))
+	assert.Nil(t, err)
+
+	_, _, err = c.ReadMessage()
+	var closeErr *CloseError
+	assert.True(t, errors.As(err, &closeErr))
+	assert.Equal(t, &CloseError{Code: CloseGoingAway, Reason: "bye"}, closeErr)
+	assert.Nil(t, c.Close())
+
+	assert.Equal(t, "/chat", got.URL.Path)
+	user, pass, _ := got.BasicAuth()
+	assert.Equal(t, "user:pass", user+":"+pass)
+	cookie, _ := got.Cookie("session")
+	assert.Equal(t, "s1", cookie.Value)
+	assert.Equal(t, "chat", got.Header.Get("Sec-WebSocket-Protocol"))
+}
+
+func TestWebSocket_Ping(t *testing.T) {
+	serverPongs := make(chan string, 1)
+	ts := websocketServer(false, func(c *WebSocket, r *http.Request) {
+		c.PongHandler = func(p []byte) { serverPongs <- string(p) }
+		_ = c.Ping([]byte("from server"))
+		echo(c, r)
+	})
+	defer ts.Close()
+
+	c, err := NewSession().WebSocket(wsURL(ts))
+	assert.Nil(t, err)
+	var pongs []string
+	c.PongHandler = func(p []byte) { pongs = append(pongs, string(p)) }
+
+	assert.Nil(t, c.Ping([]byte("from client")))
+	assert.Nil(t, c.WriteText("x"))
+	_, p, err := c.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, "x", string(p))
+	assert.Equal(t, []string{"from client"}, pongs)
+	assert.Equal(t, "from server", <-serverPongs)
+	assert.NotNil(t, c.Ping(make([]byte, 126)))
+	assert.Nil(t, c.Close())
+}
+
+func TestWebSocket_ProtocolError(t *testing.T) {
+	closed := make(chan error, 1)
+	ts := websocketServer(false, func(c *WebSocket, r *http.Request) {
+		// Servers must not mask frames.
+		_ = writeFrame(c.bw, frame{fin: true, opcode: opText, payload: []byte("x")}, true)
+		_ = c.bw.Flush()
+		_, _, err := c.ReadMessage()
+		closed <- err
+	})
+	defer ts.Close()
+
+	c, err := NewSession().WebSocket(wsURL(ts))
+	assert.Nil(t, err)
+	_, _, err = c.ReadMessage()
+	assert.ErrorContains(t, err, "invalid frame masking")
+
+	var closeErr *CloseError
+	assert.True(t, errors.As(<-closed, &closeErr))
+	assert.Equal(t, CloseProtocolError, closeErr.Code)
+}
+
+func TestWebSocket_BadHandshake(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	s := NewSession(WithBaseURL(ts.URL))
+	_, err := s.WebSocket("forbidden")
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+
+	_, err = s.WebSocket("ok")
+	assert.ErrorIs(t, err, ErrBadHandshake)
+}
+
+func TestWebSocket_CloseWhileReading(t *testing.T) {
+	conn, peer := net.Pipe()
+	c := newWebSocket(conn, true)
+	read := make(chan struct{})
+	go func() {
+		_, _, _ = c.ReadMessage()
+		close(read)
+	}()
+	go func() {
+		// The peer sends a last message after the close frame of c,
+		// but never its own close frame.
+		br := bufio.NewReader(peer)
+		for {
+			f, err := readFrame(br, defaultMaxMessageSize)
+			if err != nil {
+				return
+			}
+			if f.opcode == opClose {
+				time.Sleep(10 * time.Millisecond)
+				_ = writeFrame(peer, frame{fin: true, opcode: opText, payload: []byte("last")}, false)
+			}
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		_ = c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(closeTimeout + time.Second):
+		t.Fatal("Close did not return after the close timeout")
+	}
+	<-read
+}
+
+func TestWebSocket_ContextTakeover(t *testing.T) {
+	// The peer compresses messages with the context of the previous ones.
+	var stream bytes.Buffer
+	w, _ := flate.NewWriter(&stream, flate.BestCompression)
+	var messages [][]byte
+	for _, m := range []string{"hello websocket hello websocket", "hello websocket hello websocket!"} {
+		start := stream.Len()
+		w.Write([]byte(m))
+		w.Flush()
+		messages = append(messages, bytes.TrimSuffix(stream.Bytes()[start:], deflateTail[:4]))
+	}
+
+	c := newWebSocket(nopReadWriteCloser{}, true)
+	c.compress, c.readContextTakeover = true, true
+	for i, want := range []string{"hello websocket hello websocket", "hello websocket hello websocket!"} {
+		p, err := c.inflate(messages[i], defaultMaxMessageSize)
+		assert.Nil(t, err)
+		assert.Equal(t, want, string(p))
+	}
+	_, err := c.inflate([]byte("garbage"), defaultMaxMessageSize)
+	assert.NotNil(t, err)
+}
+
+func TestReadFrame_TooBig(t *testing.T) {
+	var b bytes.Buffer
+	_ = writeFrame(&b, frame{fin: true, opcode: opBinary, payload: make([]byte, 1000)}, false)
+	_, err := readFrame(bufio.NewReader(&b), 999)
+	var perr *protocolError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, CloseMessageTooBig, perr.code)
+}
+
+type nopReadWriteCloser struct{}
+
+func (nopReadWriteCloser) Read([]byte) (int, error)    { return 0, io.EOF }
+func (nopReadWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (nopReadWriteCloser) Close() error                { return nil }